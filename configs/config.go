@@ -4,6 +4,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/azka-zaydan/synapsis-test/shared/money"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 )
@@ -40,6 +41,12 @@ type Config struct {
 		Cart struct {
 			ExpiresIn time.Duration `mapstructure:"EXPIRES_IN"`
 		} `mapstructure:"CART"`
+		Idempotency struct {
+			ExpiresIn time.Duration `mapstructure:"EXPIRES_IN"`
+		} `mapstructure:"IDEMPOTENCY"`
+		CheckoutQuote struct {
+			ExpiresIn time.Duration `mapstructure:"EXPIRES_IN"`
+		} `mapstructure:"CHECKOUT_QUOTE"`
 	}
 
 	DB struct {
@@ -100,6 +107,30 @@ type Config struct {
 		}
 	}
 
+	Inventory struct {
+		Reservation struct {
+			TTL           time.Duration `mapstructure:"TTL"`
+			SweepInterval time.Duration `mapstructure:"SWEEP_INTERVAL"`
+		} `mapstructure:"RESERVATION"`
+	} `mapstructure:"INVENTORY"`
+
+	Payment struct {
+		AllowedMethods string `mapstructure:"ALLOWED_METHODS"`
+		Webhook        struct {
+			Secret    string        `mapstructure:"SECRET"`
+			Tolerance time.Duration `mapstructure:"TOLERANCE"`
+		} `mapstructure:"WEBHOOK"`
+	} `mapstructure:"PAYMENT"`
+
+	Currency struct {
+		Default string `mapstructure:"DEFAULT"`
+	} `mapstructure:"CURRENCY"`
+
+	Order struct {
+		PaymentTimeout      time.Duration `mapstructure:"PAYMENT_TIMEOUT"`
+		ExpirySweepInterval time.Duration `mapstructure:"EXPIRY_SWEEP_INTERVAL"`
+	} `mapstructure:"ORDER"`
+
 	JWT struct {
 		ExpiresIn time.Duration `mapstructure:"EXPIRES_IN"`
 		Key       string        `mapstructure:"KEY"`
@@ -140,3 +171,13 @@ func Get() *Config {
 
 	return &conf
 }
+
+// DefaultCurrency returns the currency of prices and carts that do not name
+// one.
+func (c *Config) DefaultCurrency() money.Currency {
+	currency, err := money.ParseCurrency(c.Currency.Default)
+	if err != nil {
+		return money.DefaultCurrency
+	}
+	return currency
+}
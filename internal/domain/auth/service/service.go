@@ -70,7 +70,7 @@ func (s *AuthServiceImpl) Register(ctx context.Context, req dto.RegisterDto) (re
 		return
 	}
 
-	token, err := s.JwtService.GenerateJWT(user.Username, user.ID)
+	token, err := s.JwtService.GenerateJWT(user.Username, user.ID, user.Role)
 	if err != nil {
 		log.Error().Err(err).Msg("[Register] Failed Generate Token")
 		return
@@ -131,7 +131,7 @@ func (s *AuthServiceImpl) Login(ctx context.Context, req dto.RegisterDto) (res d
 		log.Error().Err(err).Msg("[Login] Invalid Password")
 		return
 	}
-	token, err = s.JwtService.GenerateJWT(user.Username, user.ID.String())
+	token, err = s.JwtService.GenerateJWT(user.Username, user.ID.String(), user.Role)
 	if err != nil {
 		log.Error().Err(err).Msg("[Login] Failed Generate Token")
 		return
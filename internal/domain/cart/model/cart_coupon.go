@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+// CartCoupon is a coupon code applied to a cart, waiting to be redeemed at
+// checkout.
+type CartCoupon struct {
+	ID            uuid.UUID   `db:"id"`
+	CartID        uuid.UUID   `db:"cart_id"`
+	PromotionID   uuid.UUID   `db:"promotion_id"`
+	Code          string      `db:"code"`
+	CreatedBy     uuid.UUID   `db:"created_by"`
+	MetaCreatedAt time.Time   `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID   `db:"updated_by"`
+	MetaUpdatedAt time.Time   `db:"meta_updated_at"`
+	DeletedBy     null.String `db:"deleted_by"`
+	MetaDeletedAt null.Time   `db:"meta_deleted_at"`
+}
+
+func NewCartCoupon(cartID, promotionID uuid.UUID, code string, by uuid.UUID) CartCoupon {
+	id, _ := uuid.NewV4()
+	return CartCoupon{
+		ID:          id,
+		CartID:      cartID,
+		PromotionID: promotionID,
+		Code:        code,
+		CreatedBy:   by,
+		UpdatedBy:   by,
+	}
+}
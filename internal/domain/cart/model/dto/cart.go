@@ -4,26 +4,64 @@ import (
 	"time"
 
 	"github.com/azka-zaydan/synapsis-test/internal/domain/cart/model"
+	promotionModel "github.com/azka-zaydan/synapsis-test/internal/domain/promotion/model"
+	promotionDto "github.com/azka-zaydan/synapsis-test/internal/domain/promotion/model/dto"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
 	"github.com/gofrs/uuid"
 	"github.com/guregu/null"
 )
 
 type CartResponse struct {
-	ID            string      `json:"id"`
-	UserID        string      `json:"userId"`
-	TotalItems    int         `json:"totalItems"`
-	TotalPrice    float64     `json:"totalPrice"`
-	CreatedBy     string      `json:"createdBy"`
-	MetaCreatedAt time.Time   `json:"metaCreatedAt"`
-	UpdatedBy     string      `json:"updatedBy"`
-	MetaUpdatedAt time.Time   `json:"metaUpdatedAt"`
-	DeletedBy     null.String `json:"deletedBy"`
-	MetaDeletedAt null.Time   `json:"metaDeletedAt"`
+	ID            string         `json:"id"`
+	UserID        string         `json:"userId"`
+	TotalItems    int            `json:"totalItems"`
+	TotalPrice    money.Amount   `json:"totalPrice"`
+	Currency      money.Currency `json:"currency"`
+	CreatedBy     string         `json:"createdBy"`
+	MetaCreatedAt time.Time      `json:"metaCreatedAt"`
+	UpdatedBy     string         `json:"updatedBy"`
+	MetaUpdatedAt time.Time      `json:"metaUpdatedAt"`
+	DeletedBy     null.String    `json:"deletedBy"`
+	MetaDeletedAt null.Time      `json:"metaDeletedAt"`
 }
 
 type ListItemsResponse struct {
-	Cart  CartResponse
-	Items []CartItemResponse
+	Cart      CartResponse
+	Items     []CartItemResponse
+	Discounts *DiscountSummaryResponse `json:",omitempty"`
+}
+
+// DiscountSummaryResponse breaks down the promotions applied to the cart.
+// Total is what the cart costs after them.
+type DiscountSummaryResponse struct {
+	Coupons       []string                        `json:"coupons"`
+	Applied       []promotionDto.DiscountResponse `json:"applied"`
+	DiscountTotal money.Amount                    `json:"discountTotal"`
+	Total         money.Amount                    `json:"total"`
+	FreeShipping  bool                            `json:"freeShipping"`
+}
+
+// ApplyCouponRequest attaches a coupon code to the cart.
+type ApplyCouponRequest struct {
+	Code string `json:"code"`
+}
+
+func NewDiscountSummaryResponse(cart model.Cart, coupons []model.CartCoupon, evaluation promotionModel.Evaluation) DiscountSummaryResponse {
+	codes := make([]string, 0, len(coupons))
+	for _, coupon := range coupons {
+		codes = append(codes, coupon.Code)
+	}
+	applied := make([]promotionDto.DiscountResponse, 0, len(evaluation.Applied))
+	for _, v := range evaluation.Applied {
+		applied = append(applied, promotionDto.NewDiscountResponse(v))
+	}
+	return DiscountSummaryResponse{
+		Coupons:       codes,
+		Applied:       applied,
+		DiscountTotal: evaluation.DiscountTotal,
+		Total:         cart.TotalPrice - evaluation.DiscountTotal,
+		FreeShipping:  evaluation.FreeShipping,
+	}
 }
 
 func NewCartResponse(cart model.Cart) CartResponse {
@@ -32,6 +70,7 @@ func NewCartResponse(cart model.Cart) CartResponse {
 		UserID:        cart.UserID.String(),
 		TotalItems:    cart.TotalItems,
 		TotalPrice:    cart.TotalPrice,
+		Currency:      cart.Currency,
 		CreatedBy:     cart.CreatedBy.String(),
 		MetaCreatedAt: cart.MetaCreatedAt,
 		UpdatedBy:     cart.UpdatedBy.String(),
@@ -56,10 +95,16 @@ func NewListItemsResponse(cart model.Cart, items []model.CartItem) ListItemsResp
 }
 
 type CreateCartRequest struct {
-	UserID string `json:"userId"`
+	UserID   string `json:"userId"`
+	Currency string `json:"currency"`
 }
 
-func (d *CreateCartRequest) ToModel() (res model.Cart, err error) {
+// SetCurrencyRequest switches the currency the cart is priced in.
+type SetCurrencyRequest struct {
+	Currency string `json:"currency"`
+}
+
+func (d *CreateCartRequest) ToModel(defaultCurrency money.Currency) (res model.Cart, err error) {
 	id, err := uuid.NewV4()
 	if err != nil {
 		return
@@ -68,9 +113,17 @@ func (d *CreateCartRequest) ToModel() (res model.Cart, err error) {
 	if err != nil {
 		return
 	}
+	currency := defaultCurrency
+	if d.Currency != "" {
+		currency, err = money.ParseCurrency(d.Currency)
+		if err != nil {
+			return
+		}
+	}
 	return model.Cart{
 		ID:         id,
 		UserID:     userId,
+		Currency:   currency,
 		TotalPrice: 0,
 		TotalItems: 0,
 		CreatedBy:  userId,
@@ -4,22 +4,26 @@ import (
 	"time"
 
 	"github.com/azka-zaydan/synapsis-test/internal/domain/cart/model"
+	orderModel "github.com/azka-zaydan/synapsis-test/internal/domain/order/model"
+	promotionDto "github.com/azka-zaydan/synapsis-test/internal/domain/promotion/model/dto"
+	shippingDto "github.com/azka-zaydan/synapsis-test/internal/domain/shipping/model/dto"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
 	"github.com/gofrs/uuid"
 	"github.com/guregu/null"
 )
 
 type CartItemResponse struct {
-	ID            string      `json:"id"`
-	CartID        string      `json:"cartId"`
-	ProductID     string      `json:"productId"`
-	TotalPrice    float64     `json:"totalPrice"`
-	Quantity      int         `json:"quantity"`
-	CreatedBy     string      `json:"createdBy"`
-	MetaCreatedAt time.Time   `json:"metaCreatedAt"`
-	UpdatedBy     string      `json:"updatedBy"`
-	MetaUpdatedAt time.Time   `json:"metaUpdatedAt"`
-	DeletedBy     null.String `json:"deletedBy"`
-	MetaDeletedAt null.Time   `json:"metaDeletedAt"`
+	ID            string       `json:"id"`
+	CartID        string       `json:"cartId"`
+	ProductID     string       `json:"productId"`
+	TotalPrice    money.Amount `json:"totalPrice"`
+	Quantity      int          `json:"quantity"`
+	CreatedBy     string       `json:"createdBy"`
+	MetaCreatedAt time.Time    `json:"metaCreatedAt"`
+	UpdatedBy     string       `json:"updatedBy"`
+	MetaUpdatedAt time.Time    `json:"metaUpdatedAt"`
+	DeletedBy     null.String  `json:"deletedBy"`
+	MetaDeletedAt null.Time    `json:"metaDeletedAt"`
 }
 
 func NewCartItemResponse(item model.CartItem) CartItemResponse {
@@ -40,50 +44,167 @@ func NewCartItemResponse(item model.CartItem) CartItemResponse {
 
 type AddItemsRequest []ItemRequest
 
-type CheckoutRequest []CheckoutItem
+// CheckoutRequest checks out items of the cart and ships them with
+// ShippingMethod to an address of the user's address book, the default
+// address when AddressID is empty. Items that cannot be checked out are
+// left in the cart, unless Strict is set and then nothing is checked out.
+// QuoteToken holds the checkout to a preview: it is rejected unless it
+// orders the same items for the same totals as the preview quoted.
+type CheckoutRequest struct {
+	AddressID      string         `json:"addressId"`
+	ShippingMethod string         `json:"shippingMethod"`
+	Items          []CheckoutItem `json:"items"`
+	Strict         bool           `json:"strict"`
+	QuoteToken     string         `json:"quoteToken"`
+}
+
+type CheckoutLineStatus string
+
+const (
+	CheckoutLineAccepted          CheckoutLineStatus = "accepted"
+	CheckoutLineInsufficientStock CheckoutLineStatus = "insufficient_stock"
+	CheckoutLineQuantityMismatch  CheckoutLineStatus = "quantity_mismatch"
+	CheckoutLineNotInCart         CheckoutLineStatus = "not_in_cart"
+	CheckoutLineDuplicate         CheckoutLineStatus = "duplicate"
+	CheckoutLineUnavailable       CheckoutLineStatus = "unavailable"
+)
+
+// CheckoutLineResponse reports what became of one requested item.
+// CartQuantity and Stock are set when they are why the item was rejected.
+type CheckoutLineResponse struct {
+	ItemID       string             `json:"itemId"`
+	ProductID    string             `json:"productId"`
+	Quantity     int                `json:"quantity"`
+	Status       CheckoutLineStatus `json:"status"`
+	CartQuantity null.Int           `json:"cartQuantity"`
+	Stock        null.Int           `json:"stock"`
+}
+
+// ShippingRatesRequest asks what shipping the cart costs to an address, the
+// default address when AddressID is empty.
+type ShippingRatesRequest struct {
+	AddressID string `query:"addressId"`
+}
+
+// ShippingQuoteResponse is a shipping method the cart can be shipped with.
+type ShippingQuoteResponse = shippingDto.QuoteResponse
 
 type DeleteItemsRequest []DeleteItemRequest
 
+// ItemRequest adds a product to the cart. Prices are resolved from the
+// catalog, so the request carries no price.
 type ItemRequest struct {
-	ProductID string  `json:"productId"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
+	ProductID string `json:"productId"`
+	Quantity  int    `json:"quantity"`
 }
 
 type CheckoutItem struct {
-	ItemId    string  `json:"itemId"`
-	ProductId string  `json:"productId"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
+	ItemId    string `json:"itemId"`
+	ProductId string `json:"productId"`
+	Quantity  int    `json:"quantity"`
 }
 
 type CartItemCreateRequest struct {
-	CartID    string  `json:"cartId"`
-	ProductID string  `json:"productId"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
+	CartID    string       `json:"cartId"`
+	ProductID string       `json:"productId"`
+	Quantity  int          `json:"quantity"`
+	UnitPrice money.Amount `json:"unitPrice"`
 }
 
 type CheckoutResponse struct {
-	OrderID    string    `json:"orderId"`
-	OrderAt    time.Time `json:"orderAt"`
-	TotalItems int       `json:"totalItems"`
-	TotalPrice float64   `json:"totalPrice"`
+	OrderID    string       `json:"orderId"`
+	OrderAt    time.Time    `json:"orderAt"`
+	TotalItems int          `json:"totalItems"`
+	TotalPrice money.Amount `json:"totalPrice"`
+	// DiscountTotal is what promotions took off, TotalPrice is net of it.
+	DiscountTotal money.Amount `json:"discountTotal"`
+	// TaxAmount is the tax of the order, TotalPrice includes it.
+	TaxAmount money.Amount `json:"taxAmount"`
+	// ShippingAmount is what ShippingMethod costs, TotalPrice includes it.
+	// It is zero when a promotion grants free shipping.
+	ShippingMethod string         `json:"shippingMethod"`
+	ShippingAmount money.Amount   `json:"shippingAmount"`
+	Currency       money.Currency `json:"currency"`
+	// Lines report every requested item in the order of the request.
+	Lines []CheckoutLineResponse `json:"lines"`
 }
 
-func NewCheckoutResponse(orderId string, orderAt time.Time, totalItems int, totalPrice float64) CheckoutResponse {
+func NewCheckoutResponse(order orderModel.Order, lines []CheckoutLineResponse) CheckoutResponse {
+	var totalItems int
+	for _, line := range lines {
+		if line.Status == CheckoutLineAccepted {
+			totalItems++
+		}
+	}
 	return CheckoutResponse{
-		OrderID:    orderId,
-		OrderAt:    orderAt,
-		TotalItems: totalItems,
-		TotalPrice: totalPrice,
+		OrderID:        order.ID.String(),
+		OrderAt:        order.OrderAt,
+		TotalItems:     totalItems,
+		TotalPrice:     order.TotalPrice,
+		DiscountTotal:  order.DiscountTotal,
+		TaxAmount:      order.TaxAmount,
+		ShippingMethod: order.ShippingMethod,
+		ShippingAmount: order.ShippingAmount,
+		Currency:       order.Currency,
+		Lines:          lines,
+	}
+}
+
+// CheckoutPreviewResponse prices a checkout without placing it. Items and
+// Totals cover the accepted lines. QuoteToken is empty when the checkout
+// would be rejected.
+type CheckoutPreviewResponse struct {
+	Items          []CheckoutPreviewItemResponse   `json:"items"`
+	Discounts      []promotionDto.DiscountResponse `json:"discounts"`
+	Totals         CheckoutTotalsResponse          `json:"totals"`
+	ShippingMethod string                          `json:"shippingMethod"`
+	Currency       money.Currency                  `json:"currency"`
+	// Lines report every requested item in the order of the request.
+	Lines      []CheckoutLineResponse `json:"lines"`
+	QuoteToken string                 `json:"quoteToken"`
+	ExpiresAt  null.Time              `json:"expiresAt"`
+}
+
+// CheckoutPreviewItemResponse is a priced line of a checkout preview.
+type CheckoutPreviewItemResponse struct {
+	ItemID       string       `json:"itemId"`
+	ProductID    string       `json:"productId"`
+	ProductName  string       `json:"productName"`
+	Quantity     int          `json:"quantity"`
+	UnitPrice    money.Amount `json:"unitPrice"`
+	Subtotal     money.Amount `json:"subtotal"`
+	TaxRate      int          `json:"taxRate"`
+	TaxInclusive bool         `json:"taxInclusive"`
+	TaxAmount    money.Amount `json:"taxAmount"`
+}
+
+// CheckoutTotalsResponse breaks down what a checkout costs. Total is the
+// subtotal less the discount, plus exclusive tax and shipping.
+type CheckoutTotalsResponse struct {
+	Subtotal       money.Amount `json:"subtotal"`
+	DiscountTotal  money.Amount `json:"discountTotal"`
+	TaxAmount      money.Amount `json:"taxAmount"`
+	ShippingAmount money.Amount `json:"shippingAmount"`
+	Total          money.Amount `json:"total"`
+}
+
+func NewCheckoutPreviewItemResponse(itemID string, detail orderModel.OrderDetail) CheckoutPreviewItemResponse {
+	return CheckoutPreviewItemResponse{
+		ItemID:       itemID,
+		ProductID:    detail.ProductID.String(),
+		ProductName:  detail.ProductName,
+		Quantity:     detail.TotalItems,
+		UnitPrice:    detail.UnitPrice,
+		Subtotal:     detail.SubtotalProductPrice,
+		TaxRate:      detail.TaxRate,
+		TaxInclusive: detail.TaxInclusive,
+		TaxAmount:    detail.TaxAmount,
 	}
 }
 
 type DeleteItemRequest struct {
-	ItemId   string  `json:"itemId"`
-	Quantity int     `json:"quantity"`
-	Price    float64 `json:"price"`
+	ItemId   string `json:"itemId"`
+	Quantity int    `json:"quantity"`
 }
 
 func (d *CartItemCreateRequest) ToModel() (res model.CartItem, err error) {
@@ -104,7 +225,7 @@ func (d *CartItemCreateRequest) ToModel() (res model.CartItem, err error) {
 		CartID:     cartId,
 		ProductID:  productId,
 		Quantity:   d.Quantity,
-		TotalPrice: d.Price,
+		TotalPrice: d.UnitPrice.Mul(d.Quantity),
 		CreatedBy:  cartId,
 		UpdatedBy:  cartId,
 	}, nil
@@ -7,6 +7,7 @@ import (
 	"github.com/azka-zaydan/synapsis-test/infras"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/cart/model"
 	"github.com/azka-zaydan/synapsis-test/shared/logger"
+	"github.com/jmoiron/sqlx"
 )
 
 type CartRepository interface {
@@ -17,6 +18,12 @@ type CartRepository interface {
 	UpdateCart(ctx context.Context, cart *model.Cart) (err error)
 	UpdateCartItem(ctx context.Context, item *model.CartItem) (err error)
 	DeleteCartItem(ctx context.Context, cartID string) (err error)
+	UpdateCartTx(ctx context.Context, tx *sqlx.Tx, cart *model.Cart) (err error)
+	DeleteCartItemTx(ctx context.Context, tx *sqlx.Tx, itemID string) (err error)
+	UpdateCartItemTx(ctx context.Context, tx *sqlx.Tx, item *model.CartItem) (err error)
+	CreateCartCoupon(ctx context.Context, coupon *model.CartCoupon) (err error)
+	GetCartCouponsByCartID(ctx context.Context, cartId string) (res []model.CartCoupon, err error)
+	DeleteCartCouponTx(ctx context.Context, tx *sqlx.Tx, cartId string, promotionId string) (err error)
 }
 
 type CartRepositoryMySQL struct {
@@ -92,12 +99,66 @@ func (repo *CartRepositoryMySQL) DeleteCartItem(ctx context.Context, cartID stri
 	return
 }
 
+func (repo *CartRepositoryMySQL) UpdateCartTx(ctx context.Context, tx *sqlx.Tx, cart *model.Cart) (err error) {
+	_, err = tx.NamedExecContext(ctx, cartUpdateQuery, cart)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *CartRepositoryMySQL) UpdateCartItemTx(ctx context.Context, tx *sqlx.Tx, item *model.CartItem) (err error) {
+	_, err = tx.NamedExecContext(ctx, cartItemUpdateQuery, item)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *CartRepositoryMySQL) DeleteCartItemTx(ctx context.Context, tx *sqlx.Tx, itemID string) (err error) {
+	_, err = tx.ExecContext(ctx, cartItemDeleteQuery, itemID)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *CartRepositoryMySQL) CreateCartCoupon(ctx context.Context, coupon *model.CartCoupon) (err error) {
+	_, err = repo.DB.Write.NamedExecContext(ctx, cartCouponInsertQuery, coupon)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *CartRepositoryMySQL) GetCartCouponsByCartID(ctx context.Context, cartId string) (res []model.CartCoupon, err error) {
+	err = repo.DB.Read.SelectContext(ctx, &res, cartCouponSelectQuery, cartId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *CartRepositoryMySQL) DeleteCartCouponTx(ctx context.Context, tx *sqlx.Tx, cartId string, promotionId string) (err error) {
+	_, err = tx.ExecContext(ctx, cartCouponDeleteQuery, cartId, promotionId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
 var (
 	cartInsertQuery = `
-	INSERT INTO cart (id, user_id, total_items, total_price, created_by, updated_by)
-	VALUES (:id, :user_id, :total_items, :total_price, :created_by, :updated_by)`
+	INSERT INTO cart (id, user_id, total_items, total_price, currency, created_by, updated_by)
+	VALUES (:id, :user_id, :total_items, :total_price, :currency, :created_by, :updated_by)`
 	cartSelectQuery = `
-	SELECT id, user_id, total_items, total_price, created_by, meta_created_at, updated_by, meta_updated_at, deleted_by, meta_deleted_at
+	SELECT id, user_id, total_items, total_price, currency, created_by, meta_created_at, updated_by, meta_updated_at, deleted_by, meta_deleted_at
 	FROM cart`
 	cartItemSelectQuery = `
 	SELECT id, cart_id, product_id, quantity, total_price, created_by, meta_created_at, updated_by, meta_updated_at, deleted_by, meta_deleted_at
@@ -108,7 +169,7 @@ var (
 	VALUES (:id, :cart_id, :product_id, :quantity, :total_price, :created_by, :updated_by)`
 	cartUpdateQuery = `
 	UPDATE cart 
-	SET user_id = :user_id, total_items = :total_items, total_price = :total_price, updated_by = :updated_by
+	SET user_id = :user_id, total_items = :total_items, total_price = :total_price, currency = :currency, updated_by = :updated_by
 	WHERE id = :id`
 	cartItemUpdateQuery = `
 	UPDATE cart_item 
@@ -117,4 +178,15 @@ var (
 	cartItemDeleteQuery = `
 	DELETE FROM cart_item 
 	WHERE id = ?`
+	cartCouponInsertQuery = `
+	INSERT INTO cart_coupon (id, cart_id, promotion_id, code, created_by, updated_by)
+	VALUES (:id, :cart_id, :promotion_id, :code, :created_by, :updated_by)`
+	cartCouponSelectQuery = `
+	SELECT id, cart_id, promotion_id, code, created_by, meta_created_at, updated_by, meta_updated_at, deleted_by, meta_deleted_at
+	FROM cart_coupon
+	WHERE cart_id = ?
+	ORDER BY meta_created_at, id`
+	cartCouponDeleteQuery = `
+	DELETE FROM cart_coupon
+	WHERE cart_id = ? AND promotion_id = ?`
 )
@@ -0,0 +1,706 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/cart/model"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/cart/model/dto"
+	orderModel "github.com/azka-zaydan/synapsis-test/internal/domain/order/model"
+	paymentModel "github.com/azka-zaydan/synapsis-test/internal/domain/payment/model"
+	productModel "github.com/azka-zaydan/synapsis-test/internal/domain/product/model"
+	promotionModel "github.com/azka-zaydan/synapsis-test/internal/domain/promotion/model"
+	promotionDto "github.com/azka-zaydan/synapsis-test/internal/domain/promotion/model/dto"
+	promotionSvc "github.com/azka-zaydan/synapsis-test/internal/domain/promotion/service"
+	reservationDto "github.com/azka-zaydan/synapsis-test/internal/domain/reservation/model/dto"
+	shippingModel "github.com/azka-zaydan/synapsis-test/internal/domain/shipping/model"
+	taxModel "github.com/azka-zaydan/synapsis-test/internal/domain/tax/model"
+	userModel "github.com/azka-zaydan/synapsis-test/internal/domain/user/model"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultCheckoutQuoteExpiry = 15 * time.Minute
+
+// checkoutDraft is an order priced from a checkout request before anything
+// is written. items are the cart items accepted for the order, in the order
+// of details.
+type checkoutDraft struct {
+	order      orderModel.Order
+	details    []orderModel.OrderDetail
+	discounts  []orderModel.OrderDiscount
+	evaluation promotionModel.Evaluation
+	report     []dto.CheckoutLineResponse
+	items      []model.CartItem
+	lines      []promotionModel.Line
+	weight     int
+	// categoryNames are the names of the categories of details by id.
+	categoryNames map[uuid.UUID]string
+}
+
+// checkoutQuote is what a checkout preview priced. It is kept under its
+// token so the checkout that follows can be held to it.
+type checkoutQuote struct {
+	AddressID      string         `json:"addressId"`
+	ShippingMethod string         `json:"shippingMethod"`
+	Currency       money.Currency `json:"currency"`
+	Items          map[string]int `json:"items"`
+	DiscountTotal  money.Amount   `json:"discountTotal"`
+	TaxAmount      money.Amount   `json:"taxAmount"`
+	ShippingAmount money.Amount   `json:"shippingAmount"`
+	TotalPrice     money.Amount   `json:"totalPrice"`
+}
+
+func (s *CartServiceImpl) Checkout(ctx context.Context, req dto.CheckoutRequest, userID uuid.UUID) (res dto.CheckoutResponse, err error) {
+	cart, err := s.Repo.GetCartByUserID(ctx, userID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[Checkout] Failed GetCartByUserID")
+		return
+	}
+	items, err := s.Repo.GetCartItemsByCartID(ctx, cart.ID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[Checkout] Failed GetCartItemsByCartID")
+		return
+	}
+	if len(items) == 0 {
+		return
+	}
+	address, err := s.shippingAddress(ctx, req.AddressID, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[Checkout] Failed shippingAddress")
+		return
+	}
+	var quote *checkoutQuote
+	if req.QuoteToken != "" {
+		quote, err = s.getCheckoutQuote(ctx, userID.String(), req.QuoteToken)
+		if err != nil {
+			log.Error().Err(err).Msg("[Checkout] Failed getCheckoutQuote")
+			return
+		}
+	}
+
+	order, report, err := s.parseCheckoutItems(ctx, items, req, cart, address, quote)
+	if err != nil {
+		log.Error().Err(err).Msg("[Checkout] Failed parseCheckoutItems")
+		return
+	}
+	if quote != nil {
+		// the order is placed, a quote that outlives it does no harm
+		if err := s.deleteCheckoutQuote(ctx, userID.String(), req.QuoteToken); err != nil {
+			log.Warn().Err(err).Msg("[Checkout] Failed deleteCheckoutQuote")
+		}
+	}
+	err = s.deleteListItemsCache(ctx, userID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[Checkout] Failed deleteListItemsCache")
+		return
+	}
+
+	return dto.NewCheckoutResponse(order, report), nil
+}
+
+// PreviewCheckout prices a checkout the way Checkout would place it, without
+// writing anything but the quote its token refers to.
+func (s *CartServiceImpl) PreviewCheckout(ctx context.Context, req dto.CheckoutRequest, userID uuid.UUID) (res dto.CheckoutPreviewResponse, err error) {
+	cart, err := s.Repo.GetCartByUserID(ctx, userID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[PreviewCheckout] Failed GetCartByUserID")
+		return
+	}
+	items, err := s.Repo.GetCartItemsByCartID(ctx, cart.ID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[PreviewCheckout] Failed GetCartItemsByCartID")
+		return
+	}
+	address, err := s.shippingAddress(ctx, req.AddressID, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[PreviewCheckout] Failed shippingAddress")
+		return
+	}
+	coupons, err := s.Repo.GetCartCouponsByCartID(ctx, cart.ID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[PreviewCheckout] Failed GetCartCouponsByCartID")
+		return
+	}
+
+	draft := newCheckoutDraft(cart, address)
+	err = s.selectCheckoutItems(ctx, &draft, items, req, s.getProduct)
+	if err != nil {
+		log.Error().Err(err).Msg("[PreviewCheckout] Failed selectCheckoutItems")
+		return
+	}
+	if len(draft.items) > 0 {
+		err = s.priceCheckout(ctx, &draft, cart, coupons, address, req.ShippingMethod, s.PromotionSvc.Evaluate)
+		if err != nil {
+			log.Error().Err(err).Msg("[PreviewCheckout] Failed priceCheckout")
+			return
+		}
+	}
+
+	res = newCheckoutPreviewResponse(draft)
+	if checkoutRejection(draft.report, req.Strict) != nil {
+		return res, nil
+	}
+	token, _ := uuid.NewV4()
+	expiresIn, err := s.setCheckoutQuote(ctx, userID.String(), token.String(), newCheckoutQuote(draft))
+	if err != nil {
+		log.Error().Err(err).Msg("[PreviewCheckout] Failed setCheckoutQuote")
+		return
+	}
+	res.QuoteToken = token.String()
+	res.ExpiresAt = null.TimeFrom(time.Now().Add(expiresIn))
+	return res, nil
+}
+
+// parseCheckoutItems places the order of the requested items that can be
+// checked out and reports on every requested item. When quote is set the
+// order must match it.
+func (s *CartServiceImpl) parseCheckoutItems(ctx context.Context, existingItems []model.CartItem, req dto.CheckoutRequest, cart model.Cart, address userModel.Address, quote *checkoutQuote) (res orderModel.Order, report []dto.CheckoutLineResponse, err error) {
+	coupons, err := s.Repo.GetCartCouponsByCartID(ctx, cart.ID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[parseCheckoutItems] Failed GetCartCouponsByCartID")
+		return
+	}
+
+	draft := newCheckoutDraft(cart, address)
+	order := &draft.order
+	var payment paymentModel.Payment
+	paymentId, _ := uuid.NewV4()
+	order.PaymentID = uuid.NullUUID{UUID: paymentId, Valid: true}
+	payment.ID = paymentId
+	payment.OrderID = order.ID
+	payment.Currency = cart.Currency
+	payment.CreatedBy = cart.UserID
+	payment.UpdatedBy = cart.UserID
+
+	err = s.DB.WithTransaction(func(tx *sqlx.Tx, e chan error) {
+		products, err := s.lockCheckoutProducts(ctx, tx, existingItems, req)
+		if err != nil {
+			e <- err
+			return
+		}
+		err = s.selectCheckoutItems(ctx, &draft, existingItems, req, func(ctx context.Context, productId string) (productModel.Product, error) {
+			return products[productId], nil
+		})
+		if err != nil {
+			e <- err
+			return
+		}
+		err = checkoutRejection(draft.report, req.Strict)
+		if err != nil {
+			e <- err
+			return
+		}
+		err = s.priceCheckout(ctx, &draft, cart, coupons, address, req.ShippingMethod, func(ctx context.Context, req promotionSvc.EvaluateRequest) (promotionModel.Evaluation, error) {
+			return s.PromotionSvc.RedeemTx(ctx, tx, req, order.ID)
+		})
+		if err != nil {
+			e <- err
+			return
+		}
+		if quote != nil {
+			if changed := quote.diff(newCheckoutQuote(draft)); changed != "" {
+				e <- failure.Conflict("checkout", "quote", fmt.Sprintf("%s since the preview", changed))
+				return
+			}
+		}
+
+		for i, item := range draft.items {
+			detail := draft.details[i]
+			err = s.ProductRepo.AdjustProductStockTx(ctx, tx, detail.ProductID.String(), -detail.TotalItems)
+			if err != nil {
+				e <- err
+				return
+			}
+			holdDto := reservationDto.CreateReservationRequest{
+				OrderID:   order.ID.String(),
+				ProductID: detail.ProductID.String(),
+				Quantity:  detail.TotalItems,
+				TTL:       s.config.Inventory.Reservation.TTL,
+				CreatedBy: cart.UserID.String(),
+			}
+			hold, err := holdDto.ToModel()
+			if err != nil {
+				e <- err
+				return
+			}
+			err = s.ReservationRepo.CreateReservationTx(ctx, tx, &hold)
+			if err != nil {
+				e <- err
+				return
+			}
+			err = s.Repo.DeleteCartItemTx(ctx, tx, item.ID.String())
+			if err != nil {
+				e <- err
+				return
+			}
+			cart.TotalPrice -= item.TotalPrice
+			cart.TotalItems -= 1
+		}
+
+		payment.TotalPrice = order.TotalPrice
+		payment.Conversions = order.Conversions
+		payment.Status = int(paymentModel.Unpaid)
+		payment.PaymentMethod = ""
+		payment.UserID = cart.UserID
+
+		err = s.OrderRepo.CreateOrderTx(ctx, tx, order)
+		if err != nil {
+			e <- err
+			return
+		}
+		for i := range draft.discounts {
+			err = s.OrderRepo.CreateOrderDiscountTx(ctx, tx, &draft.discounts[i])
+			if err != nil {
+				e <- err
+				return
+			}
+			err = s.Repo.DeleteCartCouponTx(ctx, tx, cart.ID.String(), draft.discounts[i].PromotionID.String())
+			if err != nil {
+				e <- err
+				return
+			}
+		}
+		history := orderModel.NewOrderStatusHistory(order.ID, null.Int{}, orderModel.OrderPlacedStatus, cart.UserID)
+		err = s.OrderRepo.CreateOrderStatusHistoryTx(ctx, tx, &history)
+		if err != nil {
+			e <- err
+			return
+		}
+		for i := range draft.details {
+			err = s.OrderRepo.CreateOrderDetailTx(ctx, tx, &draft.details[i])
+			if err != nil {
+				e <- err
+				return
+			}
+		}
+		err = s.PaymentRepo.CreatePaymentTx(ctx, tx, &payment)
+		if err != nil {
+			e <- err
+			return
+		}
+		err = s.Repo.UpdateCartTx(ctx, tx, &cart)
+		if err != nil {
+			e <- err
+			return
+		}
+		e <- nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[parseCheckoutItems] Failed Checkout Transaction")
+		return res, nil, err
+	}
+	return draft.order, draft.report, nil
+}
+
+func newCheckoutDraft(cart model.Cart, address userModel.Address) checkoutDraft {
+	orderId, _ := uuid.NewV4()
+	return checkoutDraft{
+		order: orderModel.Order{
+			ID:        orderId,
+			UserID:    cart.UserID,
+			Status:    int(orderModel.OrderPlacedStatus),
+			CreatedBy: cart.UserID,
+			UpdatedBy: cart.UserID,
+			OrderAt:   time.Now(),
+			Currency:  cart.Currency,
+			ShippingAddress: orderModel.ShippingAddress{
+				AddressID:     address.ID.String(),
+				RecipientName: address.RecipientName,
+				Phone:         address.Phone,
+				Line1:         address.Line1,
+				Line2:         address.Line2,
+				City:          address.City,
+				Region:        address.Region,
+				PostalCode:    address.PostalCode,
+				Country:       address.Country,
+			},
+		},
+	}
+}
+
+// lockCheckoutProducts locks the products of the requested items that are
+// in the cart. They are locked in the order of their ids, so checkouts of
+// the same products cannot deadlock one another.
+func (s *CartServiceImpl) lockCheckoutProducts(ctx context.Context, tx *sqlx.Tx, existingItems []model.CartItem, req dto.CheckoutRequest) (res map[string]productModel.Product, err error) {
+	requested := make(map[string]bool)
+	for _, v := range req.Items {
+		requested[v.ItemId] = true
+	}
+	seen := make(map[string]bool)
+	var ids []string
+	for _, item := range existingItems {
+		id := item.ProductID.String()
+		if !requested[item.ID.String()] || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	res = make(map[string]productModel.Product, len(ids))
+	for _, id := range ids {
+		res[id], err = s.ProductRepo.GetProductByIDForUpdate(ctx, tx, id)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// selectCheckoutItems decides which of the requested items can be checked
+// out, reporting on each of them, and prices the accepted ones.
+func (s *CartServiceImpl) selectCheckoutItems(ctx context.Context, draft *checkoutDraft, existingItems []model.CartItem, req dto.CheckoutRequest, getProduct func(ctx context.Context, productId string) (productModel.Product, error)) (err error) {
+	existingItemsMap := make(map[string]model.CartItem)
+	for _, item := range existingItems {
+		existingItemsMap[item.ID.String()] = item
+	}
+	order := &draft.order
+	draft.report = make([]dto.CheckoutLineResponse, 0, len(req.Items))
+	requested := make(map[string]bool)
+	for _, v := range req.Items {
+		line := dto.CheckoutLineResponse{
+			ItemID:    v.ItemId,
+			ProductID: v.ProductId,
+			Quantity:  v.Quantity,
+		}
+		existingItem, found := existingItemsMap[v.ItemId]
+		if !found {
+			line.Status = dto.CheckoutLineNotInCart
+			if requested[v.ItemId] {
+				line.Status = dto.CheckoutLineDuplicate
+			}
+			draft.report = append(draft.report, line)
+			continue
+		}
+		// an item listed twice in the request must only be checked out once
+		delete(existingItemsMap, v.ItemId)
+		requested[v.ItemId] = true
+		line.ProductID = existingItem.ProductID.String()
+
+		prod, err := getProduct(ctx, existingItem.ProductID.String())
+		if err != nil {
+			return err
+		}
+		if prod.MetaDeletedAt.Valid {
+			line.Status = dto.CheckoutLineUnavailable
+			draft.report = append(draft.report, line)
+			continue
+		}
+		if v.Quantity != existingItem.Quantity {
+			line.Status = dto.CheckoutLineQuantityMismatch
+			line.CartQuantity = null.IntFrom(int64(existingItem.Quantity))
+			draft.report = append(draft.report, line)
+			continue
+		}
+		if prod.Stock < existingItem.Quantity {
+			line.Status = dto.CheckoutLineInsufficientStock
+			line.Stock = null.IntFrom(int64(prod.Stock))
+			draft.report = append(draft.report, line)
+			continue
+		}
+
+		unitPrice, conversion, err := s.unitPrice(ctx, prod, order.Currency)
+		if err != nil {
+			return err
+		}
+		categoryName, err := s.categoryName(ctx, draft, prod.CategoryID)
+		if err != nil {
+			return err
+		}
+		order.Conversions.Add(conversion)
+		subtotal := unitPrice.Mul(v.Quantity)
+		id, _ := uuid.NewV4()
+		draft.details = append(draft.details, orderModel.OrderDetail{
+			ID:                   id,
+			OrderID:              order.ID,
+			ProductID:            prod.ID,
+			ProductName:          prod.Name,
+			CategoryID:           prod.CategoryID,
+			CategoryName:         categoryName,
+			UnitPrice:            unitPrice,
+			TotalItems:           v.Quantity,
+			SubtotalProductPrice: subtotal,
+			CreatedBy:            order.ID,
+			UpdatedBy:            order.ID,
+		})
+		draft.lines = append(draft.lines, promotionModel.Line{
+			ProductID:  prod.ID,
+			CategoryID: prod.CategoryID,
+			Quantity:   v.Quantity,
+			Total:      subtotal,
+		})
+		draft.items = append(draft.items, existingItem)
+		order.TotalPrice += subtotal
+		draft.weight += prod.Weight * v.Quantity
+		line.Status = dto.CheckoutLineAccepted
+		draft.report = append(draft.report, line)
+	}
+	return nil
+}
+
+// categoryName returns the name of the category the order details keep,
+// looking each category up once per draft.
+func (s *CartServiceImpl) categoryName(ctx context.Context, draft *checkoutDraft, categoryID uuid.UUID) (name string, err error) {
+	if name, ok := draft.categoryNames[categoryID]; ok {
+		return name, nil
+	}
+	category, err := s.CategoryRepo.GetCategoryByID(ctx, categoryID.String())
+	if err == sql.ErrNoRows {
+		return "", failure.UnprocessableEntity(fmt.Sprintf("category %s of a product is no longer available", categoryID))
+	}
+	if err != nil {
+		return
+	}
+	if draft.categoryNames == nil {
+		draft.categoryNames = make(map[uuid.UUID]string)
+	}
+	draft.categoryNames[categoryID] = category.Name
+	return category.Name, nil
+}
+
+// priceCheckout takes the promotions evaluate grants off the selected items
+// and adds their tax and the cost of shipping them with shippingMethod.
+func (s *CartServiceImpl) priceCheckout(ctx context.Context, draft *checkoutDraft, cart model.Cart, coupons []model.CartCoupon, address userModel.Address, shippingMethod string, evaluate func(ctx context.Context, req promotionSvc.EvaluateRequest) (promotionModel.Evaluation, error)) (err error) {
+	order := &draft.order
+	evaluation, err := evaluate(ctx, promotionSvc.EvaluateRequest{
+		UserID:       cart.UserID,
+		Currency:     cart.Currency,
+		Lines:        draft.lines,
+		PromotionIDs: couponPromotionIDs(coupons),
+	})
+	if err != nil {
+		return
+	}
+	draft.evaluation = evaluation
+	for _, applied := range evaluation.Applied {
+		id, _ := uuid.NewV4()
+		draft.discounts = append(draft.discounts, orderModel.OrderDiscount{
+			ID:           id,
+			OrderID:      order.ID,
+			PromotionID:  applied.PromotionID,
+			Code:         applied.Code,
+			Name:         applied.Name,
+			Type:         applied.Type,
+			Amount:       applied.Amount,
+			FreeShipping: applied.FreeShipping,
+			CreatedBy:    cart.UserID,
+			UpdatedBy:    cart.UserID,
+		})
+	}
+	order.DiscountTotal = evaluation.DiscountTotal
+	order.TotalPrice -= evaluation.DiscountTotal
+
+	taxAmount, exclusiveTax, err := s.taxDetails(ctx, draft.details, draft.lines, evaluation.Applied, address.Country)
+	if err != nil {
+		return
+	}
+	order.TaxAmount = taxAmount
+	order.TotalPrice += exclusiveTax
+
+	quote, err := s.ShippingSvc.Rate(ctx, shippingModel.QuoteRequest{
+		Destination: destination(address),
+		Weight:      draft.weight,
+	}, cart.Currency, shippingMethod)
+	if err != nil {
+		return
+	}
+	order.Conversions.Add(quote.Conversion)
+	order.ShippingMethod = quote.Method
+	if !evaluation.FreeShipping {
+		order.ShippingAmount = quote.Amount
+	}
+	order.TotalPrice += order.ShippingAmount
+	return nil
+}
+
+// checkoutRejection fails a checkout in which no item was accepted, or in
+// strict mode any item was rejected, naming the rejected items.
+func checkoutRejection(report []dto.CheckoutLineResponse, strict bool) error {
+	var accepted int
+	var rejected []string
+	for _, line := range report {
+		if line.Status == dto.CheckoutLineAccepted {
+			accepted++
+			continue
+		}
+		rejected = append(rejected, fmt.Sprintf("item %s: %s", line.ItemID, line.Status))
+	}
+	if accepted == 0 {
+		if len(rejected) == 0 {
+			return failure.BadRequestFromString("items must not be empty")
+		}
+		return failure.UnprocessableEntity(fmt.Sprintf("no item can be checked out (%s)", strings.Join(rejected, ", ")))
+	}
+	if strict && len(rejected) > 0 {
+		return failure.UnprocessableEntity(fmt.Sprintf("checkout rejected (%s)", strings.Join(rejected, ", ")))
+	}
+	return nil
+}
+
+// taxDetails sets the tax of each detail sold into region, charged on what
+// the line sells for once the applied promotions took their share off the
+// lines they apply to. It returns the tax of the order, and the part of it
+// that is added on top of the prices.
+func (s *CartServiceImpl) taxDetails(ctx context.Context, details []orderModel.OrderDetail, lines []promotionModel.Line, applied []promotionModel.Applied, region string) (taxAmount money.Amount, exclusiveTax money.Amount, err error) {
+	lineDiscounts := make([]money.Amount, len(details))
+	for _, a := range applied {
+		for i, share := range a.Allocate(lines) {
+			lineDiscounts[i] += share
+		}
+	}
+
+	taxLines := make([]taxModel.Line, 0, len(details))
+	for i := range details {
+		amount := details[i].SubtotalProductPrice - lineDiscounts[i]
+		if amount < 0 {
+			amount = 0
+		}
+		taxLines = append(taxLines, taxModel.Line{
+			CategoryID: lines[i].CategoryID,
+			Amount:     amount,
+		})
+	}
+	taxes, err := s.TaxSvc.Calculate(ctx, region, taxLines)
+	if err != nil {
+		log.Error().Err(err).Msg("[taxDetails] Failed Calculate")
+		return
+	}
+	for i, tax := range taxes {
+		details[i].TaxRate = tax.Rate
+		details[i].TaxInclusive = tax.Inclusive
+		details[i].TaxAmount = tax.Amount
+		taxAmount += tax.Amount
+		if !tax.Inclusive {
+			exclusiveTax += tax.Amount
+		}
+	}
+	return
+}
+
+func newCheckoutPreviewResponse(draft checkoutDraft) dto.CheckoutPreviewResponse {
+	items := make([]dto.CheckoutPreviewItemResponse, 0, len(draft.details))
+	var subtotal money.Amount
+	for i, detail := range draft.details {
+		items = append(items, dto.NewCheckoutPreviewItemResponse(draft.items[i].ID.String(), detail))
+		subtotal += detail.SubtotalProductPrice
+	}
+	discounts := make([]promotionDto.DiscountResponse, 0, len(draft.evaluation.Applied))
+	for _, applied := range draft.evaluation.Applied {
+		discounts = append(discounts, promotionDto.NewDiscountResponse(applied))
+	}
+	return dto.CheckoutPreviewResponse{
+		Items:     items,
+		Discounts: discounts,
+		Totals: dto.CheckoutTotalsResponse{
+			Subtotal:       subtotal,
+			DiscountTotal:  draft.order.DiscountTotal,
+			TaxAmount:      draft.order.TaxAmount,
+			ShippingAmount: draft.order.ShippingAmount,
+			Total:          draft.order.TotalPrice,
+		},
+		ShippingMethod: draft.order.ShippingMethod,
+		Currency:       draft.order.Currency,
+		Lines:          draft.report,
+	}
+}
+
+func newCheckoutQuote(draft checkoutDraft) checkoutQuote {
+	items := make(map[string]int, len(draft.items))
+	for _, item := range draft.items {
+		items[item.ID.String()] = item.Quantity
+	}
+	return checkoutQuote{
+		AddressID:      draft.order.ShippingAddress.AddressID,
+		ShippingMethod: draft.order.ShippingMethod,
+		Currency:       draft.order.Currency,
+		Items:          items,
+		DiscountTotal:  draft.order.DiscountTotal,
+		TaxAmount:      draft.order.TaxAmount,
+		ShippingAmount: draft.order.ShippingAmount,
+		TotalPrice:     draft.order.TotalPrice,
+	}
+}
+
+// diff names what differs between the quote and a checkout priced as
+// other, and is empty when they match.
+func (q checkoutQuote) diff(other checkoutQuote) string {
+	switch {
+	case q.AddressID != other.AddressID:
+		return "the shipping address changed"
+	case q.ShippingMethod != other.ShippingMethod:
+		return "the shipping method changed"
+	case q.Currency != other.Currency:
+		return "the currency changed"
+	case len(q.Items) != len(other.Items):
+		return "the items changed"
+	}
+	for itemID, quantity := range q.Items {
+		if other.Items[itemID] != quantity {
+			return "the items changed"
+		}
+	}
+	switch {
+	case q.DiscountTotal != other.DiscountTotal:
+		return "the discount changed"
+	case q.TaxAmount != other.TaxAmount:
+		return "the tax changed"
+	case q.ShippingAmount != other.ShippingAmount:
+		return "the shipping cost changed"
+	case q.TotalPrice != other.TotalPrice:
+		return "the total changed"
+	}
+	return ""
+}
+
+func checkoutQuoteKey(userId, token string) string {
+	return fmt.Sprintf("checkout-quote:{%s}:%s", userId, token)
+}
+
+func (s *CartServiceImpl) getCheckoutQuote(ctx context.Context, userId, token string) (res *checkoutQuote, err error) {
+	if _, err = uuid.FromString(token); err != nil {
+		return nil, failure.BadRequestFromString("quoteToken is invalid")
+	}
+	data, err := s.Redis.Client.Get(ctx, checkoutQuoteKey(userId, token)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, failure.UnprocessableEntity("quoteToken is unknown or has expired")
+		}
+		return
+	}
+	res = &checkoutQuote{}
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (s *CartServiceImpl) setCheckoutQuote(ctx context.Context, userId, token string, quote checkoutQuote) (expiresIn time.Duration, err error) {
+	marshaled, err := json.Marshal(quote)
+	if err != nil {
+		return
+	}
+	expiresIn = s.config.Cache.CheckoutQuote.ExpiresIn
+	if expiresIn <= 0 {
+		expiresIn = defaultCheckoutQuoteExpiry
+	}
+	err = s.Redis.Client.Set(ctx, checkoutQuoteKey(userId, token), marshaled, expiresIn).Err()
+	return
+}
+
+func (s *CartServiceImpl) deleteCheckoutQuote(ctx context.Context, userId, token string) (err error) {
+	return s.Redis.Client.Del(ctx, checkoutQuoteKey(userId, token)).Err()
+}
@@ -2,23 +2,36 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"sync"
-	"time"
 
 	"github.com/azka-zaydan/synapsis-test/configs"
 	"github.com/azka-zaydan/synapsis-test/infras"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/cart/model"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/cart/model/dto"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/cart/repository"
-	orderModel "github.com/azka-zaydan/synapsis-test/internal/domain/order/model"
+	categoryRepo "github.com/azka-zaydan/synapsis-test/internal/domain/category/repository"
+	currencySvc "github.com/azka-zaydan/synapsis-test/internal/domain/currency/service"
 	orderRepo "github.com/azka-zaydan/synapsis-test/internal/domain/order/repository"
-	paymentModel "github.com/azka-zaydan/synapsis-test/internal/domain/payment/model"
 	paymentRepo "github.com/azka-zaydan/synapsis-test/internal/domain/payment/repository"
+	productModel "github.com/azka-zaydan/synapsis-test/internal/domain/product/model"
 	productRepo "github.com/azka-zaydan/synapsis-test/internal/domain/product/repository"
+	promotionModel "github.com/azka-zaydan/synapsis-test/internal/domain/promotion/model"
+	promotionSvc "github.com/azka-zaydan/synapsis-test/internal/domain/promotion/service"
+	reservationRepo "github.com/azka-zaydan/synapsis-test/internal/domain/reservation/repository"
+	shippingModel "github.com/azka-zaydan/synapsis-test/internal/domain/shipping/model"
+	shippingDto "github.com/azka-zaydan/synapsis-test/internal/domain/shipping/model/dto"
+	shippingSvc "github.com/azka-zaydan/synapsis-test/internal/domain/shipping/service"
+	taxSvc "github.com/azka-zaydan/synapsis-test/internal/domain/tax/service"
+	userModel "github.com/azka-zaydan/synapsis-test/internal/domain/user/model"
+	userRepo "github.com/azka-zaydan/synapsis-test/internal/domain/user/repository"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
 
 	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 )
@@ -29,25 +42,45 @@ type CartService interface {
 	AddItems(ctx context.Context, req dto.AddItemsRequest, userID uuid.UUID) (res dto.ListItemsResponse, err error)
 	DeleteItems(ctx context.Context, req dto.DeleteItemsRequest, userID uuid.UUID) (res dto.ListItemsResponse, err error)
 	Checkout(ctx context.Context, req dto.CheckoutRequest, userID uuid.UUID) (res dto.CheckoutResponse, err error)
+	PreviewCheckout(ctx context.Context, req dto.CheckoutRequest, userID uuid.UUID) (res dto.CheckoutPreviewResponse, err error)
+	SetCurrency(ctx context.Context, req dto.SetCurrencyRequest, userID uuid.UUID) (res dto.ListItemsResponse, err error)
+	ApplyCoupon(ctx context.Context, req dto.ApplyCouponRequest, userID uuid.UUID) (res dto.ListItemsResponse, err error)
+	ShippingRates(ctx context.Context, req dto.ShippingRatesRequest, userID uuid.UUID) (res []dto.ShippingQuoteResponse, err error)
 }
 
 type CartServiceImpl struct {
-	Repo        repository.CartRepository
-	Redis       *infras.Redis
-	config      *configs.Config
-	OrderRepo   orderRepo.OrderRepository
-	PaymentRepo paymentRepo.PaymentRepository
-	ProductRepo productRepo.ProductRepository
+	Repo            repository.CartRepository
+	DB              *infras.MySQLConn
+	Redis           *infras.Redis
+	config          *configs.Config
+	OrderRepo       orderRepo.OrderRepository
+	PaymentRepo     paymentRepo.PaymentRepository
+	ProductRepo     productRepo.ProductRepository
+	ReservationRepo reservationRepo.ReservationRepository
+	CurrencySvc     currencySvc.CurrencyService
+	PromotionSvc    promotionSvc.PromotionService
+	TaxSvc          taxSvc.TaxService
+	UserRepo        userRepo.UserRepository
+	ShippingSvc     shippingSvc.ShippingService
+	CategoryRepo    categoryRepo.CategoryRepository
 }
 
-func ProvideCartServiceImpl(repo repository.CartRepository, redis *infras.Redis, config *configs.Config, orderRepo orderRepo.OrderRepository, paymentRepo paymentRepo.PaymentRepository, productRepo productRepo.ProductRepository) *CartServiceImpl {
+func ProvideCartServiceImpl(repo repository.CartRepository, db *infras.MySQLConn, redis *infras.Redis, config *configs.Config, orderRepo orderRepo.OrderRepository, paymentRepo paymentRepo.PaymentRepository, productRepo productRepo.ProductRepository, reservationRepo reservationRepo.ReservationRepository, currencySvc currencySvc.CurrencyService, promotionSvc promotionSvc.PromotionService, taxSvc taxSvc.TaxService, userRepo userRepo.UserRepository, shippingSvc shippingSvc.ShippingService, categoryRepo categoryRepo.CategoryRepository) *CartServiceImpl {
 	return &CartServiceImpl{
-		Redis:       redis,
-		Repo:        repo,
-		config:      config,
-		OrderRepo:   orderRepo,
-		PaymentRepo: paymentRepo,
-		ProductRepo: productRepo,
+		DB:              db,
+		Redis:           redis,
+		Repo:            repo,
+		config:          config,
+		OrderRepo:       orderRepo,
+		PaymentRepo:     paymentRepo,
+		ProductRepo:     productRepo,
+		ReservationRepo: reservationRepo,
+		CurrencySvc:     currencySvc,
+		PromotionSvc:    promotionSvc,
+		TaxSvc:          taxSvc,
+		UserRepo:        userRepo,
+		ShippingSvc:     shippingSvc,
+		CategoryRepo:    categoryRepo,
 	}
 }
 
@@ -78,6 +111,12 @@ func (s *CartServiceImpl) ListItems(ctx context.Context, userID uuid.UUID) (res
 	}
 
 	res = dto.NewListItemsResponse(cart, items)
+	discounts, err := s.discountSummary(ctx, cart, items)
+	if err != nil {
+		log.Error().Err(err).Msg("[ListItems] Failed discountSummary")
+		return
+	}
+	res.Discounts = &discounts
 
 	err = s.setListItemsCache(ctx, userID.String(), res)
 	if err != nil {
@@ -85,7 +124,90 @@ func (s *CartServiceImpl) ListItems(ctx context.Context, userID uuid.UUID) (res
 		return
 	}
 
-	return dto.NewListItemsResponse(cart, items), nil
+	return res, nil
+}
+
+// discountSummary evaluates the promotions that apply to the cart as it is.
+func (s *CartServiceImpl) discountSummary(ctx context.Context, cart model.Cart, items []model.CartItem) (res dto.DiscountSummaryResponse, err error) {
+	coupons, err := s.Repo.GetCartCouponsByCartID(ctx, cart.ID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[discountSummary] Failed GetCartCouponsByCartID")
+		return
+	}
+	lines := make([]promotionModel.Line, 0, len(items))
+	for _, item := range items {
+		prod, err := s.getProduct(ctx, item.ProductID.String())
+		if err != nil {
+			log.Error().Err(err).Msg("[discountSummary] Failed Get Product")
+			return res, err
+		}
+		lines = append(lines, promotionModel.Line{
+			ProductID:  prod.ID,
+			CategoryID: prod.CategoryID,
+			Quantity:   item.Quantity,
+			Total:      item.TotalPrice,
+		})
+	}
+	evaluation, err := s.PromotionSvc.Evaluate(ctx, promotionSvc.EvaluateRequest{
+		UserID:       cart.UserID,
+		Currency:     cart.Currency,
+		Lines:        lines,
+		PromotionIDs: couponPromotionIDs(coupons),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[discountSummary] Failed Evaluate")
+		return
+	}
+	return dto.NewDiscountSummaryResponse(cart, coupons, evaluation), nil
+}
+
+// ApplyCoupon attaches a coupon to the cart. Whether it takes effect is
+// decided by the promotion rules each time the cart is priced.
+func (s *CartServiceImpl) ApplyCoupon(ctx context.Context, req dto.ApplyCouponRequest, userID uuid.UUID) (res dto.ListItemsResponse, err error) {
+	promotion, err := s.PromotionSvc.GetCoupon(ctx, req.Code, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[ApplyCoupon] Failed GetCoupon")
+		return
+	}
+	cart, err := s.Repo.GetCartByUserID(ctx, userID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[ApplyCoupon] Failed GetCartByUserID")
+		return
+	}
+	coupons, err := s.Repo.GetCartCouponsByCartID(ctx, cart.ID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[ApplyCoupon] Failed GetCartCouponsByCartID")
+		return
+	}
+	applied := false
+	for _, coupon := range coupons {
+		if coupon.PromotionID == promotion.ID {
+			applied = true
+			break
+		}
+	}
+	if !applied {
+		coupon := model.NewCartCoupon(cart.ID, promotion.ID, promotion.Code.String, userID)
+		err = s.Repo.CreateCartCoupon(ctx, &coupon)
+		if err != nil {
+			log.Error().Err(err).Msg("[ApplyCoupon] Failed CreateCartCoupon")
+			return
+		}
+	}
+	err = s.deleteListItemsCache(ctx, userID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[ApplyCoupon] Failed deleteListItemsCache")
+		return
+	}
+	return s.ListItems(ctx, userID)
+}
+
+func couponPromotionIDs(coupons []model.CartCoupon) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(coupons))
+	for _, coupon := range coupons {
+		ids = append(ids, coupon.PromotionID)
+	}
+	return ids
 }
 
 func (s *CartServiceImpl) isListItemsCacheAvailable(ctx context.Context, userId string) (exist bool, err error) {
@@ -138,9 +260,10 @@ func (s *CartServiceImpl) deleteListItemsCache(ctx context.Context, userId strin
 }
 
 func (s *CartServiceImpl) CreateCart(ctx context.Context, req dto.CreateCartRequest) (res dto.CartResponse, err error) {
-	cart, err := req.ToModel()
+	cart, err := req.ToModel(s.config.DefaultCurrency())
 	if err != nil {
 		log.Error().Err(err).Msg("[CreateCart] Failed Creating Model")
+		err = failure.BadRequest(err)
 		return
 	}
 	err = s.Repo.CreateCart(ctx, &cart)
@@ -189,8 +312,30 @@ func (s *CartServiceImpl) addOrUpdateItems(ctx context.Context, existingItems []
 	for i, item := range existingItems {
 		existingItemsMap[item.ProductID.String()] = &existingItems[i]
 	}
+	// a product listed more than once is added once, with the quantities summed
+	var merged []dto.ItemRequest
+	mergedIndex := make(map[string]int)
+	for _, v := range req {
+		productId, err := uuid.FromString(v.ProductID)
+		if err != nil {
+			return make([]model.CartItem, 0), model.Cart{}, failure.BadRequest(err)
+		}
+		if v.Quantity <= 0 {
+			return make([]model.CartItem, 0), model.Cart{}, failure.BadRequestFromString("quantity must be greater than zero")
+		}
+		v.ProductID = productId.String()
+		if i, found := mergedIndex[v.ProductID]; found {
+			merged[i].Quantity += v.Quantity
+			continue
+		}
+		mergedIndex[v.ProductID] = len(merged)
+		merged = append(merged, v)
+	}
+	req = merged
+
 	var mu sync.Mutex
 	var wg sync.WaitGroup
+	var createdProductIDs []string
 	errCh := make(chan error, len(req))
 	newItemsCh := make(chan model.CartItem, len(req))
 	for _, newItem := range req {
@@ -198,44 +343,59 @@ func (s *CartServiceImpl) addOrUpdateItems(ctx context.Context, existingItems []
 		go func(newItem dto.ItemRequest) {
 			defer wg.Done()
 
+			prod, err := s.getProduct(ctx, newItem.ProductID)
+			if err != nil {
+				log.Error().Err(err).Msg("[addOrUpdateItems] Failed Get Product")
+				errCh <- err
+				return
+			}
+			if prod.MetaDeletedAt.Valid {
+				errCh <- failure.UnprocessableEntity(fmt.Sprintf("product %s is no longer available", newItem.ProductID))
+				return
+			}
+
+			unitPrice, _, err := s.unitPrice(ctx, prod, cart.Currency)
+			if err != nil {
+				log.Error().Err(err).Msg("[addOrUpdateItems] Failed Pricing Product")
+				errCh <- err
+				return
+			}
+
 			mu.Lock()
+			defer mu.Unlock()
 			if existingItem, found := existingItemsMap[newItem.ProductID]; found {
 				existingItem.Quantity += newItem.Quantity
-				existingItem.TotalPrice += newItem.Price
-				mu.Unlock()
+				existingItem.TotalPrice = unitPrice.Mul(existingItem.Quantity)
 
 				err = s.Repo.UpdateCartItem(ctx, existingItem)
 				if err != nil {
 					log.Error().Err(err).Msg("[addOrUpdateItems] Failed Update Cart Item")
 					errCh <- err
+					return
 				}
-
-				mu.Lock()
-				cart.TotalPrice += existingItem.TotalPrice
 				newItemsCh <- *existingItem
-				mu.Unlock()
 			} else {
-				mu.Unlock()
 				newItemDto := dto.CartItemCreateRequest{
 					CartID:    cart.ID.String(),
 					ProductID: newItem.ProductID,
 					Quantity:  newItem.Quantity,
-					Price:     newItem.Price,
+					UnitPrice: unitPrice,
 				}
-				newItem, err := newItemDto.ToModel()
+				item, err := newItemDto.ToModel()
 				if err != nil {
 					log.Error().Err(err).Msg("[addOrUpdateItems] Failed Create Model")
 					errCh <- err
+					return
 				}
-				err = s.Repo.CreateCartItem(ctx, &newItem)
+				err = s.Repo.CreateCartItem(ctx, &item)
 				if err != nil {
 					log.Error().Err(err).Msg("[addOrUpdateItems] Failed Create Cart Item")
 					errCh <- err
+					return
 				}
-				mu.Lock()
-				cart.TotalPrice += newItem.TotalPrice
-				newItemsCh <- newItem
-				mu.Unlock()
+				existingItemsMap[newItem.ProductID] = &item
+				createdProductIDs = append(createdProductIDs, newItem.ProductID)
+				newItemsCh <- item
 			}
 		}(newItem)
 
@@ -255,7 +415,13 @@ func (s *CartServiceImpl) addOrUpdateItems(ctx context.Context, existingItems []
 		newItems = append(newItems, item)
 	}
 
-	cart.TotalItems = len(newItems)
+	// existing items were updated in place, created ones only live in the map
+	items := make([]model.CartItem, 0, len(existingItems)+len(createdProductIDs))
+	items = append(items, existingItems...)
+	for _, productId := range createdProductIDs {
+		items = append(items, *existingItemsMap[productId])
+	}
+	recalculateCart(&cart, items)
 	if err := s.Repo.UpdateCart(ctx, &cart); err != nil {
 		log.Error().Err(err).Msg("[addOrUpdateItems] Failed Update Cart")
 		return make([]model.CartItem, 0), model.Cart{}, err
@@ -298,6 +464,12 @@ func (s *CartServiceImpl) removeOrUpdateItems(ctx context.Context, existingItems
 	for i, item := range existingItems {
 		existingItemsMap[item.ID.String()] = &existingItems[i]
 	}
+	for _, v := range req {
+		if v.Quantity <= 0 {
+			return make([]model.CartItem, 0), model.Cart{}, failure.BadRequestFromString("quantity must be greater than zero")
+		}
+	}
+
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 	errCh := make(chan error, len(req))
@@ -314,30 +486,40 @@ func (s *CartServiceImpl) removeOrUpdateItems(ctx context.Context, existingItems
 			if !found {
 				return
 			}
+			prod, err := s.getProduct(ctx, existingItem.ProductID.String())
+			if err != nil {
+				errCh <- err
+				return
+			}
+			unitPrice, _, err := s.unitPrice(ctx, prod, cart.Currency)
+			if err != nil {
+				errCh <- err
+				return
+			}
+
 			mu.Lock()
+			defer mu.Unlock()
+			if _, found := existingItemsMap[v.ItemId]; !found {
+				return
+			}
 			if v.Quantity >= existingItem.Quantity {
 				err := s.Repo.DeleteCartItem(ctx, existingItem.ID.String())
 				if err != nil {
 					errCh <- err
-					mu.Unlock()
 					return
 				}
-				cart.TotalPrice -= existingItem.TotalPrice
-				cart.TotalItems -= 1
+				delete(existingItemsMap, v.ItemId)
 			} else {
 				existingItem.Quantity -= v.Quantity
-				existingItem.TotalPrice -= v.Price
+				existingItem.TotalPrice = unitPrice.Mul(existingItem.Quantity)
 
 				err := s.Repo.UpdateCartItem(ctx, existingItem)
 				if err != nil {
 					errCh <- err
-					mu.Unlock()
 					return
 				}
-				cart.TotalPrice -= v.Price
 				updatedItemsCh <- *existingItem
 			}
-			mu.Unlock()
 		}(v)
 	}
 
@@ -356,7 +538,11 @@ func (s *CartServiceImpl) removeOrUpdateItems(ctx context.Context, existingItems
 		updatedItems = append(updatedItems, item)
 	}
 
-	cart.TotalItems = len(updatedItems)
+	remainingItems := make([]model.CartItem, 0, len(existingItemsMap))
+	for _, item := range existingItemsMap {
+		remainingItems = append(remainingItems, *item)
+	}
+	recalculateCart(&cart, remainingItems)
 	err = s.Repo.UpdateCart(ctx, &cart)
 	if err != nil {
 		log.Error().Err(err).Msg("[removeOrUpdateItems] Failed Update Cart")
@@ -365,135 +551,175 @@ func (s *CartServiceImpl) removeOrUpdateItems(ctx context.Context, existingItems
 	return updatedItems, cart, nil
 }
 
-func (s *CartServiceImpl) Checkout(ctx context.Context, req dto.CheckoutRequest, userID uuid.UUID) (res dto.CheckoutResponse, err error) {
+// ShippingRates returns the shipping methods the whole cart can be shipped
+// with to an address, priced in the cart currency.
+func (s *CartServiceImpl) ShippingRates(ctx context.Context, req dto.ShippingRatesRequest, userID uuid.UUID) (res []dto.ShippingQuoteResponse, err error) {
+	address, err := s.shippingAddress(ctx, req.AddressID, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[ShippingRates] Failed shippingAddress")
+		return
+	}
 	cart, err := s.Repo.GetCartByUserID(ctx, userID.String())
 	if err != nil {
-		log.Error().Err(err).Msg("[Checkout] Failed GetCartByUserID")
+		log.Error().Err(err).Msg("[ShippingRates] Failed GetCartByUserID")
 		return
 	}
 	items, err := s.Repo.GetCartItemsByCartID(ctx, cart.ID.String())
 	if err != nil {
-		log.Error().Err(err).Msg("[Checkout] Failed GetCartItemsByCartID")
+		log.Error().Err(err).Msg("[ShippingRates] Failed GetCartItemsByCartID")
 		return
 	}
-	if len(items) == 0 {
-		return
+	var weight int
+	for _, item := range items {
+		prod, err := s.getProduct(ctx, item.ProductID.String())
+		if err != nil {
+			log.Error().Err(err).Msg("[ShippingRates] Failed getProduct")
+			return nil, err
+		}
+		weight += prod.Weight * item.Quantity
 	}
 
-	order, totalItems, err := s.parseCheckoutItems(ctx, items, req, cart)
+	quotes, err := s.ShippingSvc.Quote(ctx, shippingModel.QuoteRequest{
+		Destination: destination(address),
+		Weight:      weight,
+	}, cart.Currency)
 	if err != nil {
-		log.Error().Err(err).Msg("[Checkout] Failed parseCheckoutItems")
+		log.Error().Err(err).Msg("[ShippingRates] Failed Quote")
 		return
 	}
+	return shippingDto.NewQuoteListResponse(quotes), nil
+}
 
-	return dto.NewCheckoutResponse(order.ID.String(), order.OrderAt, totalItems, order.TotalPrice), nil
+// shippingAddress returns the address of the user's address book with
+// addressID, or the default address when addressID is empty.
+func (s *CartServiceImpl) shippingAddress(ctx context.Context, addressID string, userID uuid.UUID) (res userModel.Address, err error) {
+	if addressID == "" {
+		addresses, err := s.UserRepo.GetAddressesByUserID(ctx, userID.String())
+		if err != nil {
+			return res, err
+		}
+		for _, address := range addresses {
+			if address.IsDefault {
+				return address, nil
+			}
+		}
+		return res, failure.UnprocessableEntity("addressId is required when there is no default address")
+	}
+	if _, err = uuid.FromString(addressID); err != nil {
+		return res, failure.BadRequest(err)
+	}
+	res, err = s.UserRepo.GetAddressByID(ctx, addressID, userID.String())
+	if err == sql.ErrNoRows {
+		err = failure.NotFound("address")
+	}
+	return
 }
 
-func (s *CartServiceImpl) parseCheckoutItems(ctx context.Context, existingItems []model.CartItem, req dto.CheckoutRequest, cart model.Cart) (res orderModel.Order, totalItems int, err error) {
-	existingItemsMap := make(map[string]*model.CartItem)
-	for i, item := range existingItems {
-		existingItemsMap[item.ID.String()] = &existingItems[i]
+func destination(address userModel.Address) shippingModel.Destination {
+	return shippingModel.Destination{
+		Country:    address.Country,
+		Region:     address.Region,
+		City:       address.City,
+		PostalCode: address.PostalCode,
 	}
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	errCh := make(chan error, len(req))
+}
 
-	var order orderModel.Order
-	var payment paymentModel.Payment
-	orderId, _ := uuid.NewV4()
-	paymentId, _ := uuid.NewV4()
-	order.ID = orderId
-	order.UserID = cart.UserID
-	order.Status = int(orderModel.OrderPlacedStatus)
-	order.PaymentID = uuid.NullUUID{UUID: paymentId, Valid: true}
-	order.CreatedBy = cart.UserID
-	order.UpdatedBy = cart.UserID
-	order.OrderAt = time.Now()
-	payment.ID = paymentId
-	payment.OrderID = orderId
-	payment.CreatedBy = cart.UserID
-	payment.UpdatedBy = cart.UserID
+func (s *CartServiceImpl) getProduct(ctx context.Context, productId string) (res productModel.Product, err error) {
+	res, err = s.ProductRepo.GetProductByID(ctx, productId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = failure.NotFound("product")
+		}
+		return
+	}
+	return
+}
 
-	for _, v := range req {
-		wg.Add(1)
-		go func(v dto.CheckoutItem) {
-			defer wg.Done()
+// SetCurrency switches the cart to another currency and reprices its items
+// with the rates in effect now.
+func (s *CartServiceImpl) SetCurrency(ctx context.Context, req dto.SetCurrencyRequest, userID uuid.UUID) (res dto.ListItemsResponse, err error) {
+	currency, err := money.ParseCurrency(req.Currency)
+	if err != nil {
+		log.Error().Err(err).Msg("[SetCurrency] Invalid Currency")
+		return res, failure.BadRequest(err)
+	}
+	cart, err := s.Repo.GetCartByUserID(ctx, userID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[SetCurrency] Failed GetCartByUserID")
+		return
+	}
+	items, err := s.Repo.GetCartItemsByCartID(ctx, cart.ID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[SetCurrency] Failed GetCartItemsByCartID")
+		return
+	}
 
-			mu.Lock()
-			existingItem, found := existingItemsMap[v.ItemId]
-			mu.Unlock()
-			if !found {
-				return
-			}
-			prod, err := s.ProductRepo.GetProductByID(ctx, v.ProductId)
+	cart.Currency = currency
+	for i := range items {
+		prod, err := s.getProduct(ctx, items[i].ProductID.String())
+		if err != nil {
+			log.Error().Err(err).Msg("[SetCurrency] Failed Get Product")
+			return res, err
+		}
+		unitPrice, _, err := s.unitPrice(ctx, prod, currency)
+		if err != nil {
+			log.Error().Err(err).Msg("[SetCurrency] Failed Pricing Product")
+			return res, err
+		}
+		items[i].TotalPrice = unitPrice.Mul(items[i].Quantity)
+	}
+	recalculateCart(&cart, items)
+
+	err = s.DB.WithTransaction(func(tx *sqlx.Tx, e chan error) {
+		var err error
+		for i := range items {
+			err = s.Repo.UpdateCartItemTx(ctx, tx, &items[i])
 			if err != nil {
-				errCh <- err
+				e <- err
 				return
 			}
-
-			mu.Lock()
-			if v.Quantity == existingItem.Quantity && prod.Stock >= existingItem.Quantity {
-				id, _ := uuid.NewV4()
-				detail := orderModel.OrderDetail{
-					ID:                   id,
-					OrderID:              order.ID,
-					ProductID:            prod.ID,
-					TotalItems:           v.Quantity,
-					SubtotalProductPrice: v.Price,
-					CreatedBy:            order.ID,
-					UpdatedBy:            order.ID,
-				}
-				err := s.Repo.DeleteCartItem(ctx, existingItem.ID.String())
-				if err != nil {
-					errCh <- err
-					mu.Unlock()
-					return
-				}
-				err = s.OrderRepo.CreateOrderDetail(ctx, &detail)
-				if err != nil {
-					errCh <- err
-					mu.Unlock()
-					return
-				}
-				prod.Stock -= v.Quantity
-				order.TotalPrice += v.Price
-				cart.TotalPrice -= existingItem.TotalPrice
-				cart.TotalItems -= 1
-				totalItems += 1
-			}
-			mu.Unlock()
-		}(v)
-	}
-	wg.Wait()
-	close(errCh)
-
-	for err := range errCh {
+		}
+		err = s.Repo.UpdateCartTx(ctx, tx, &cart)
 		if err != nil {
-			log.Error().Err(err).Msg("[parseCheckoutItems] Error Occured")
-			return res, 0, err
+			e <- err
+			return
 		}
+		e <- nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[SetCurrency] Failed Reprice Transaction")
+		return
 	}
-
-	payment.TotalPrice = order.TotalPrice
-	payment.Status = int(paymentModel.Unpaid)
-	payment.PaymentMethod = ""
-	payment.UserID = cart.UserID
-
-	err = s.OrderRepo.CreateOrder(ctx, &order)
+	err = s.deleteListItemsCache(ctx, userID.String())
 	if err != nil {
-		log.Error().Err(err).Msg("[parseCheckoutItems] Failed Create Order")
+		log.Error().Err(err).Msg("[SetCurrency] Failed deleteListItemsCache")
 		return
 	}
-
-	err = s.PaymentRepo.CreatePayment(ctx, &payment)
+	res = dto.NewListItemsResponse(cart, items)
+	discounts, err := s.discountSummary(ctx, cart, items)
 	if err != nil {
-		log.Error().Err(err).Msg("[parseCheckoutItems] Failed Create Payment")
+		log.Error().Err(err).Msg("[SetCurrency] Failed discountSummary")
 		return
 	}
-	err = s.Repo.UpdateCart(ctx, &cart)
+	res.Discounts = &discounts
+	return res, nil
+}
+
+// unitPrice returns the price of one unit of the product in currency, with
+// the conversion used to get it.
+func (s *CartServiceImpl) unitPrice(ctx context.Context, prod productModel.Product, currency money.Currency) (res money.Amount, conversion money.Conversion, err error) {
+	conversion, err = s.CurrencySvc.Conversion(ctx, prod.Currency, currency)
 	if err != nil {
-		log.Error().Err(err).Msg("[parseCheckoutItems] Failed Update Cart")
 		return
 	}
-	return order, totalItems, nil
+	return conversion.Apply(prod.Price), conversion, nil
+}
+
+// recalculateCart sets the cart totals from the items it currently holds.
+func recalculateCart(cart *model.Cart, items []model.CartItem) {
+	cart.TotalPrice = 0
+	for _, item := range items {
+		cart.TotalPrice += item.TotalPrice
+	}
+	cart.TotalItems = len(items)
 }
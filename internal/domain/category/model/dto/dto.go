@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/category/model"
+	"github.com/gofrs/uuid"
+)
+
+// CategoryRequest creates or replaces a category. Leave ParentID empty to
+// put the category at the top of the tree.
+type CategoryRequest struct {
+	Name     string `json:"name"`
+	ParentID string `json:"parentId"`
+}
+
+type CategoryResponse struct {
+	ID            string        `json:"id"`
+	ParentID      uuid.NullUUID `json:"parentId"`
+	Name          string        `json:"name"`
+	CreatedBy     string        `json:"createdBy"`
+	MetaCreatedAt time.Time     `json:"metaCreatedAt"`
+	UpdatedBy     string        `json:"updatedBy"`
+	MetaUpdatedAt time.Time     `json:"metaUpdatedAt"`
+}
+
+// CategoryTreeResponse is a category with the categories below it.
+type CategoryTreeResponse struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name"`
+	Children []CategoryTreeResponse `json:"children"`
+}
+
+func (d *CategoryRequest) ToModel(by uuid.UUID) (res model.Category, err error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return
+	}
+	res = model.Category{
+		ID:        id,
+		CreatedBy: by,
+	}
+	err = d.Apply(&res, by)
+	return
+}
+
+func (d *CategoryRequest) Apply(category *model.Category, by uuid.UUID) (err error) {
+	if strings.TrimSpace(d.Name) == "" {
+		return errors.New("name must not be empty")
+	}
+	var parentID uuid.NullUUID
+	if d.ParentID != "" {
+		id, err := uuid.FromString(d.ParentID)
+		if err != nil {
+			return err
+		}
+		if id == category.ID {
+			return errors.New("a category cannot be its own parent")
+		}
+		parentID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+	category.Name = strings.TrimSpace(d.Name)
+	category.ParentID = parentID
+	category.UpdatedBy = by
+	return nil
+}
+
+func NewCategoryResponse(category model.Category) CategoryResponse {
+	return CategoryResponse{
+		ID:            category.ID.String(),
+		ParentID:      category.ParentID,
+		Name:          category.Name,
+		CreatedBy:     category.CreatedBy.String(),
+		MetaCreatedAt: category.MetaCreatedAt,
+		UpdatedBy:     category.UpdatedBy.String(),
+		MetaUpdatedAt: category.MetaUpdatedAt,
+	}
+}
+
+func NewCategoryListResponse(categories []model.Category) []CategoryResponse {
+	res := make([]CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		res = append(res, NewCategoryResponse(category))
+	}
+	return res
+}
+
+// NewCategoryTreeResponse nests the categories under their parents,
+// starting from the top level.
+func NewCategoryTreeResponse(categories []model.Category) []CategoryTreeResponse {
+	return newCategoryTree(model.Children(categories), uuid.Nil)
+}
+
+func newCategoryTree(children map[uuid.UUID][]model.Category, parentID uuid.UUID) []CategoryTreeResponse {
+	res := make([]CategoryTreeResponse, 0, len(children[parentID]))
+	for _, category := range children[parentID] {
+		res = append(res, CategoryTreeResponse{
+			ID:       category.ID.String(),
+			Name:     category.Name,
+			Children: newCategoryTree(children, category.ID),
+		})
+	}
+	return res
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+// Category groups products. Categories nest under ParentID, a category
+// without a parent is at the top of the tree.
+type Category struct {
+	ID            uuid.UUID     `db:"id"`
+	ParentID      uuid.NullUUID `db:"parent_id"`
+	Name          string        `db:"name"`
+	CreatedBy     uuid.UUID     `db:"created_by"`
+	MetaCreatedAt time.Time     `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID     `db:"updated_by"`
+	MetaUpdatedAt time.Time     `db:"meta_updated_at"`
+	DeletedBy     uuid.NullUUID `db:"deleted_by"`
+	MetaDeletedAt null.Time     `db:"meta_deleted_at"`
+}
+
+// Children groups categories by the id of their parent. The top level is
+// under uuid.Nil. Categories keep the order they are given in.
+func Children(categories []Category) map[uuid.UUID][]Category {
+	res := make(map[uuid.UUID][]Category)
+	for _, category := range categories {
+		parentID := uuid.Nil
+		if category.ParentID.Valid {
+			parentID = category.ParentID.UUID
+		}
+		res[parentID] = append(res[parentID], category)
+	}
+	return res
+}
+
+// Descendants returns id followed by the ids of all categories below it.
+func Descendants(categories []Category, id uuid.UUID) []uuid.UUID {
+	children := Children(categories)
+	res := []uuid.UUID{id}
+	seen := map[uuid.UUID]bool{id: true}
+	for i := 0; i < len(res); i++ {
+		for _, child := range children[res[i]] {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			res = append(res, child.ID)
+		}
+	}
+	return res
+}
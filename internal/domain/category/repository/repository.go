@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azka-zaydan/synapsis-test/infras"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/category/model"
+	"github.com/azka-zaydan/synapsis-test/shared/logger"
+)
+
+type CategoryRepository interface {
+	CreateCategory(ctx context.Context, category *model.Category) (err error)
+	UpdateCategory(ctx context.Context, category *model.Category) (err error)
+	DeleteCategory(ctx context.Context, category *model.Category) (err error)
+	GetCategories(ctx context.Context) (res []model.Category, err error)
+	GetCategoryByID(ctx context.Context, categoryId string) (res model.Category, err error)
+}
+
+type CategoryRepositoryMySQL struct {
+	DB *infras.MySQLConn
+}
+
+func ProvideCategoryRepositoryMySQL(db *infras.MySQLConn) *CategoryRepositoryMySQL {
+	return &CategoryRepositoryMySQL{
+		DB: db,
+	}
+}
+
+func (repo *CategoryRepositoryMySQL) CreateCategory(ctx context.Context, category *model.Category) (err error) {
+	_, err = repo.DB.Write.NamedExecContext(ctx, categoryInsertQuery, category)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *CategoryRepositoryMySQL) UpdateCategory(ctx context.Context, category *model.Category) (err error) {
+	_, err = repo.DB.Write.NamedExecContext(ctx, categoryUpdateQuery, category)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *CategoryRepositoryMySQL) DeleteCategory(ctx context.Context, category *model.Category) (err error) {
+	_, err = repo.DB.Write.NamedExecContext(ctx, categoryDeleteQuery, category)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *CategoryRepositoryMySQL) GetCategories(ctx context.Context) (res []model.Category, err error) {
+	err = repo.DB.Read.SelectContext(ctx, &res, fmt.Sprintf("%s WHERE meta_deleted_at IS NULL ORDER BY name, id", categorySelectQuery))
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *CategoryRepositoryMySQL) GetCategoryByID(ctx context.Context, categoryId string) (res model.Category, err error) {
+	err = repo.DB.Read.GetContext(ctx, &res, fmt.Sprintf("%s WHERE id = ? AND meta_deleted_at IS NULL", categorySelectQuery), categoryId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+var (
+	categoryInsertQuery = `
+	INSERT INTO category (
+		id,
+		parent_id,
+		name,
+		created_by,
+		updated_by
+	) VALUES (
+		:id,
+		:parent_id,
+		:name,
+		:created_by,
+		:updated_by
+	)`
+	categoryUpdateQuery = `
+	UPDATE category SET
+		parent_id = :parent_id,
+		name = :name,
+		updated_by = :updated_by
+	WHERE id = :id AND meta_deleted_at IS NULL`
+	categoryDeleteQuery = `
+	UPDATE category SET
+		deleted_by = :deleted_by,
+		meta_deleted_at = CURRENT_TIMESTAMP
+	WHERE id = :id AND meta_deleted_at IS NULL`
+	categorySelectQuery = `
+	SELECT
+		id,
+		parent_id,
+		name,
+		created_by,
+		meta_created_at,
+		updated_by,
+		meta_updated_at,
+		deleted_by,
+		meta_deleted_at
+	FROM category`
+)
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/category/model"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/category/model/dto"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/category/repository"
+	productRepo "github.com/azka-zaydan/synapsis-test/internal/domain/product/repository"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type CategoryService interface {
+	CreateCategory(ctx context.Context, req dto.CategoryRequest, userID uuid.UUID) (res dto.CategoryResponse, err error)
+	ListCategories(ctx context.Context) (res []dto.CategoryResponse, err error)
+	GetCategoryTree(ctx context.Context) (res []dto.CategoryTreeResponse, err error)
+	GetCategory(ctx context.Context, categoryID string) (res dto.CategoryResponse, err error)
+	UpdateCategory(ctx context.Context, categoryID string, req dto.CategoryRequest, userID uuid.UUID) (res dto.CategoryResponse, err error)
+	DeleteCategory(ctx context.Context, categoryID string, userID uuid.UUID) (err error)
+	Descendants(ctx context.Context, categoryID string) (res []uuid.UUID, err error)
+}
+
+type CategoryServiceImpl struct {
+	Repo        repository.CategoryRepository
+	ProductRepo productRepo.ProductRepository
+}
+
+func ProvideCategoryServiceImpl(repo repository.CategoryRepository, productRepo productRepo.ProductRepository) *CategoryServiceImpl {
+	return &CategoryServiceImpl{
+		Repo:        repo,
+		ProductRepo: productRepo,
+	}
+}
+
+func (s *CategoryServiceImpl) CreateCategory(ctx context.Context, req dto.CategoryRequest, userID uuid.UUID) (res dto.CategoryResponse, err error) {
+	category, err := req.ToModel(userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateCategory] Invalid Category")
+		return res, failure.BadRequest(err)
+	}
+	err = s.checkParent(ctx, category)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateCategory] Failed checkParent")
+		return
+	}
+	err = s.Repo.CreateCategory(ctx, &category)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateCategory] Failed CreateCategory")
+		return
+	}
+	return s.GetCategory(ctx, category.ID.String())
+}
+
+func (s *CategoryServiceImpl) ListCategories(ctx context.Context) (res []dto.CategoryResponse, err error) {
+	categories, err := s.Repo.GetCategories(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("[ListCategories] Failed GetCategories")
+		return
+	}
+	return dto.NewCategoryListResponse(categories), nil
+}
+
+func (s *CategoryServiceImpl) GetCategoryTree(ctx context.Context) (res []dto.CategoryTreeResponse, err error) {
+	categories, err := s.Repo.GetCategories(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("[GetCategoryTree] Failed GetCategories")
+		return
+	}
+	return dto.NewCategoryTreeResponse(categories), nil
+}
+
+func (s *CategoryServiceImpl) GetCategory(ctx context.Context, categoryID string) (res dto.CategoryResponse, err error) {
+	category, err := s.getCategory(ctx, categoryID)
+	if err != nil {
+		log.Error().Err(err).Msg("[GetCategory] Failed getCategory")
+		return
+	}
+	return dto.NewCategoryResponse(category), nil
+}
+
+// UpdateCategory renames a category or moves it, with the categories below
+// it, under another parent.
+func (s *CategoryServiceImpl) UpdateCategory(ctx context.Context, categoryID string, req dto.CategoryRequest, userID uuid.UUID) (res dto.CategoryResponse, err error) {
+	category, err := s.getCategory(ctx, categoryID)
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateCategory] Failed getCategory")
+		return
+	}
+	err = req.Apply(&category, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateCategory] Invalid Category")
+		return res, failure.BadRequest(err)
+	}
+	err = s.checkParent(ctx, category)
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateCategory] Failed checkParent")
+		return
+	}
+	err = s.Repo.UpdateCategory(ctx, &category)
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateCategory] Failed UpdateCategory")
+		return
+	}
+	return s.GetCategory(ctx, categoryID)
+}
+
+// DeleteCategory deletes a category that has no categories or products
+// below it.
+func (s *CategoryServiceImpl) DeleteCategory(ctx context.Context, categoryID string, userID uuid.UUID) (err error) {
+	category, err := s.getCategory(ctx, categoryID)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeleteCategory] Failed getCategory")
+		return
+	}
+	categories, err := s.Repo.GetCategories(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeleteCategory] Failed GetCategories")
+		return
+	}
+	if len(model.Children(categories)[category.ID]) > 0 {
+		return failure.Conflict("delete", "category", "category has subcategories")
+	}
+	products, err := s.ProductRepo.CountProductsByCategoryID(ctx, categoryID)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeleteCategory] Failed CountProductsByCategoryID")
+		return
+	}
+	if products > 0 {
+		return failure.Conflict("delete", "category", "category has products")
+	}
+	category.DeletedBy = uuid.NullUUID{UUID: userID, Valid: true}
+	err = s.Repo.DeleteCategory(ctx, &category)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeleteCategory] Failed DeleteCategory")
+		return
+	}
+	return nil
+}
+
+// Descendants returns the id of the category followed by the ids of all
+// categories below it.
+func (s *CategoryServiceImpl) Descendants(ctx context.Context, categoryID string) (res []uuid.UUID, err error) {
+	category, err := s.getCategory(ctx, categoryID)
+	if err != nil {
+		log.Error().Err(err).Msg("[Descendants] Failed getCategory")
+		return
+	}
+	categories, err := s.Repo.GetCategories(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("[Descendants] Failed GetCategories")
+		return
+	}
+	return model.Descendants(categories, category.ID), nil
+}
+
+// checkParent makes sure the parent of category exists and is not below
+// the category itself.
+func (s *CategoryServiceImpl) checkParent(ctx context.Context, category model.Category) (err error) {
+	if !category.ParentID.Valid {
+		return nil
+	}
+	categories, err := s.Repo.GetCategories(ctx)
+	if err != nil {
+		return
+	}
+	var found bool
+	for _, c := range categories {
+		if c.ID == category.ParentID.UUID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return failure.UnprocessableEntity("parent category not found")
+	}
+	for _, id := range model.Descendants(categories, category.ID) {
+		if id == category.ParentID.UUID {
+			return failure.UnprocessableEntity("a category cannot be moved below itself")
+		}
+	}
+	return nil
+}
+
+func (s *CategoryServiceImpl) getCategory(ctx context.Context, categoryID string) (res model.Category, err error) {
+	if _, err = uuid.FromString(categoryID); err != nil {
+		return res, failure.BadRequest(err)
+	}
+	res, err = s.Repo.GetCategoryByID(ctx, categoryID)
+	if err == sql.ErrNoRows {
+		err = failure.NotFound("category")
+	}
+	return
+}
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/currency/model"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+type RateRequest struct {
+	BaseCurrency  string     `json:"baseCurrency"`
+	QuoteCurrency string     `json:"quoteCurrency"`
+	Rate          money.Rate `json:"rate"`
+}
+
+// LoadRatesRequest loads a batch of rates taking effect at EffectiveAt, or
+// immediately when it is empty.
+type LoadRatesRequest struct {
+	Rates       []RateRequest `json:"rates"`
+	EffectiveAt null.Time     `json:"effectiveAt"`
+}
+
+type ExchangeRateResponse struct {
+	ID            string         `json:"id"`
+	BaseCurrency  money.Currency `json:"baseCurrency"`
+	QuoteCurrency money.Currency `json:"quoteCurrency"`
+	Rate          money.Rate     `json:"rate"`
+	EffectiveAt   time.Time      `json:"effectiveAt"`
+	CreatedBy     string         `json:"createdBy"`
+	MetaCreatedAt time.Time      `json:"metaCreatedAt"`
+}
+
+func (d *RateRequest) ToModel(effectiveAt time.Time, by uuid.UUID) (res model.ExchangeRate, err error) {
+	base, err := money.ParseCurrency(d.BaseCurrency)
+	if err != nil {
+		return
+	}
+	quote, err := money.ParseCurrency(d.QuoteCurrency)
+	if err != nil {
+		return
+	}
+	if base == quote {
+		return res, errors.New("base and quote currency must differ")
+	}
+	if !d.Rate.IsPositive() {
+		return res, errors.New("rate must be greater than zero")
+	}
+	id, err := uuid.NewV4()
+	if err != nil {
+		return
+	}
+	return model.ExchangeRate{
+		ID:            id,
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          d.Rate,
+		EffectiveAt:   effectiveAt,
+		CreatedBy:     by,
+		UpdatedBy:     by,
+	}, nil
+}
+
+func NewExchangeRateResponse(rate model.ExchangeRate) ExchangeRateResponse {
+	return ExchangeRateResponse{
+		ID:            rate.ID.String(),
+		BaseCurrency:  rate.BaseCurrency,
+		QuoteCurrency: rate.QuoteCurrency,
+		Rate:          rate.Rate,
+		EffectiveAt:   rate.EffectiveAt,
+		CreatedBy:     rate.CreatedBy.String(),
+		MetaCreatedAt: rate.MetaCreatedAt,
+	}
+}
+
+func NewExchangeRateListResponse(rates []model.ExchangeRate) []ExchangeRateResponse {
+	res := make([]ExchangeRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		res = append(res, NewExchangeRateResponse(rate))
+	}
+	return res
+}
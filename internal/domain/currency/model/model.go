@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+// ExchangeRate says one unit of BaseCurrency is worth Rate units of
+// QuoteCurrency from EffectiveAt on, until a later rate of the same pair
+// takes effect.
+type ExchangeRate struct {
+	ID            uuid.UUID      `db:"id"`
+	BaseCurrency  money.Currency `db:"base_currency"`
+	QuoteCurrency money.Currency `db:"quote_currency"`
+	Rate          money.Rate     `db:"rate"`
+	EffectiveAt   time.Time      `db:"effective_at"`
+	CreatedBy     uuid.UUID      `db:"created_by"`
+	MetaCreatedAt time.Time      `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID      `db:"updated_by"`
+	MetaUpdatedAt time.Time      `db:"meta_updated_at"`
+	DeletedBy     uuid.NullUUID  `db:"deleted_by"`
+	MetaDeletedAt null.Time      `db:"meta_deleted_at"`
+}
+
+// Conversion returns the conversion from BaseCurrency to QuoteCurrency, or
+// the inverse one when inverted is set.
+func (m ExchangeRate) Conversion(inverted bool) money.Conversion {
+	if inverted {
+		return money.Conversion{
+			From:        m.QuoteCurrency,
+			To:          m.BaseCurrency,
+			Rate:        m.Rate,
+			Inverted:    true,
+			EffectiveAt: m.EffectiveAt,
+		}
+	}
+	return money.Conversion{
+		From:        m.BaseCurrency,
+		To:          m.QuoteCurrency,
+		Rate:        m.Rate,
+		EffectiveAt: m.EffectiveAt,
+	}
+}
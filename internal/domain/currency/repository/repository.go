@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/infras"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/currency/model"
+	"github.com/azka-zaydan/synapsis-test/shared/logger"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/jmoiron/sqlx"
+)
+
+type CurrencyRepository interface {
+	CreateExchangeRateTx(ctx context.Context, tx *sqlx.Tx, rate *model.ExchangeRate) (err error)
+	GetEffectiveExchangeRate(ctx context.Context, base money.Currency, quote money.Currency, at time.Time) (res model.ExchangeRate, err error)
+	GetEffectiveExchangeRates(ctx context.Context, at time.Time) (res []model.ExchangeRate, err error)
+}
+
+type CurrencyRepositoryMySQL struct {
+	DB *infras.MySQLConn
+}
+
+func ProvideCurrencyRepositoryMySQL(db *infras.MySQLConn) *CurrencyRepositoryMySQL {
+	return &CurrencyRepositoryMySQL{
+		DB: db,
+	}
+}
+
+func (repo *CurrencyRepositoryMySQL) CreateExchangeRateTx(ctx context.Context, tx *sqlx.Tx, rate *model.ExchangeRate) (err error) {
+	_, err = tx.NamedExecContext(ctx, exchangeRateInsertQuery, rate)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *CurrencyRepositoryMySQL) GetEffectiveExchangeRate(ctx context.Context, base money.Currency, quote money.Currency, at time.Time) (res model.ExchangeRate, err error) {
+	query := fmt.Sprintf("%s WHERE base_currency = ? AND quote_currency = ? AND effective_at <= ? ORDER BY effective_at DESC, meta_created_at DESC LIMIT 1", exchangeRateSelectQuery)
+	err = repo.DB.Read.GetContext(ctx, &res, query, base, quote, at)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *CurrencyRepositoryMySQL) GetEffectiveExchangeRates(ctx context.Context, at time.Time) (res []model.ExchangeRate, err error) {
+	err = repo.DB.Read.SelectContext(ctx, &res, exchangeRateEffectiveQuery, at, at)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+var (
+	exchangeRateInsertQuery = `
+	INSERT INTO exchange_rate (
+		id,
+		base_currency,
+		quote_currency,
+		rate,
+		effective_at,
+		created_by,
+		updated_by
+	) VALUES (
+		:id,
+		:base_currency,
+		:quote_currency,
+		:rate,
+		:effective_at,
+		:created_by,
+		:updated_by
+	)`
+	exchangeRateSelectQuery = `
+	SELECT
+		id,
+		base_currency,
+		quote_currency,
+		rate,
+		effective_at,
+		created_by,
+		meta_created_at,
+		updated_by,
+		meta_updated_at
+	FROM exchange_rate`
+	exchangeRateEffectiveQuery = `
+	SELECT
+		er.id,
+		er.base_currency,
+		er.quote_currency,
+		er.rate,
+		er.effective_at,
+		er.created_by,
+		er.meta_created_at,
+		er.updated_by,
+		er.meta_updated_at
+	FROM exchange_rate er
+	JOIN (
+		SELECT base_currency, quote_currency, MAX(effective_at) AS effective_at
+		FROM exchange_rate
+		WHERE effective_at <= ?
+		GROUP BY base_currency, quote_currency
+	) latest ON latest.base_currency = er.base_currency
+		AND latest.quote_currency = er.quote_currency
+		AND latest.effective_at = er.effective_at
+	WHERE er.effective_at <= ?
+	ORDER BY er.base_currency, er.quote_currency`
+)
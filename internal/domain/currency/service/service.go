@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/infras"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/currency/model"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/currency/model/dto"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/currency/repository"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type CurrencyService interface {
+	LoadRates(ctx context.Context, req dto.LoadRatesRequest, adminID uuid.UUID) (res []dto.ExchangeRateResponse, err error)
+	ListRates(ctx context.Context) (res []dto.ExchangeRateResponse, err error)
+	Conversion(ctx context.Context, from money.Currency, to money.Currency) (res money.Conversion, err error)
+}
+
+type CurrencyServiceImpl struct {
+	Repo repository.CurrencyRepository
+	DB   *infras.MySQLConn
+}
+
+func ProvideCurrencyServiceImpl(repo repository.CurrencyRepository, db *infras.MySQLConn) *CurrencyServiceImpl {
+	return &CurrencyServiceImpl{
+		Repo: repo,
+		DB:   db,
+	}
+}
+
+// LoadRates stores a batch of exchange rates in one transaction, so a
+// partly invalid batch loads nothing.
+func (s *CurrencyServiceImpl) LoadRates(ctx context.Context, req dto.LoadRatesRequest, adminID uuid.UUID) (res []dto.ExchangeRateResponse, err error) {
+	if len(req.Rates) == 0 {
+		err = failure.BadRequestFromString("rates must not be empty")
+		log.Error().Err(err).Msg("[LoadRates] Empty Rates")
+		return
+	}
+	effectiveAt := time.Now()
+	if req.EffectiveAt.Valid {
+		effectiveAt = req.EffectiveAt.Time
+	}
+
+	rates := make([]model.ExchangeRate, 0, len(req.Rates))
+	pairs := make(map[string]bool)
+	for _, v := range req.Rates {
+		rate, err := v.ToModel(effectiveAt, adminID)
+		if err != nil {
+			log.Error().Err(err).Msg("[LoadRates] Invalid Rate")
+			return nil, failure.UnprocessableEntity(err.Error())
+		}
+		pair := fmt.Sprintf("%s/%s", rate.BaseCurrency, rate.QuoteCurrency)
+		if pairs[pair] {
+			return nil, failure.UnprocessableEntity(fmt.Sprintf("rate %s is listed more than once", pair))
+		}
+		pairs[pair] = true
+		rates = append(rates, rate)
+	}
+
+	err = s.DB.WithTransaction(func(tx *sqlx.Tx, e chan error) {
+		for i := range rates {
+			err := s.Repo.CreateExchangeRateTx(ctx, tx, &rates[i])
+			if err != nil {
+				e <- err
+				return
+			}
+		}
+		e <- nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[LoadRates] Failed Load Transaction")
+		return
+	}
+	return dto.NewExchangeRateListResponse(rates), nil
+}
+
+// ListRates returns the rate in effect for every loaded pair.
+func (s *CurrencyServiceImpl) ListRates(ctx context.Context) (res []dto.ExchangeRateResponse, err error) {
+	rates, err := s.Repo.GetEffectiveExchangeRates(ctx, time.Now())
+	if err != nil {
+		log.Error().Err(err).Msg("[ListRates] Failed GetEffectiveExchangeRates")
+		return
+	}
+	return dto.NewExchangeRateListResponse(rates), nil
+}
+
+// Conversion returns how to convert from one currency into another with
+// the rates in effect now. A pair loaded only in the opposite direction is
+// used inverted.
+func (s *CurrencyServiceImpl) Conversion(ctx context.Context, from money.Currency, to money.Currency) (res money.Conversion, err error) {
+	if from == to {
+		return money.Conversion{From: from, To: to}, nil
+	}
+	now := time.Now()
+	rate, err := s.Repo.GetEffectiveExchangeRate(ctx, from, to, now)
+	if err == nil {
+		return rate.Conversion(false), nil
+	}
+	if err != sql.ErrNoRows {
+		log.Error().Err(err).Msg("[Conversion] Failed GetEffectiveExchangeRate")
+		return
+	}
+	rate, err = s.Repo.GetEffectiveExchangeRate(ctx, to, from, now)
+	if err == nil {
+		return rate.Conversion(true), nil
+	}
+	if err == sql.ErrNoRows {
+		err = failure.UnprocessableEntity(fmt.Sprintf("no exchange rate from %s to %s", from, to))
+	}
+	log.Error().Err(err).Msg("[Conversion] Failed GetEffectiveExchangeRate")
+	return
+}
@@ -4,33 +4,50 @@ import (
 	"time"
 
 	"github.com/azka-zaydan/synapsis-test/internal/domain/order/model"
+	promotionModel "github.com/azka-zaydan/synapsis-test/internal/domain/promotion/model"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
 	"github.com/gofrs/uuid"
 	"github.com/guregu/null"
 )
 
 type OrderResponse struct {
-	ID            uuid.UUID     `json:"id"`
-	UserID        uuid.UUID     `json:"userId"`
-	PaymentID     uuid.NullUUID `json:"paymentId,omitempty"`
-	TotalPrice    float64       `json:"totalPrice"`
-	Status        int           `json:"status"`
-	OrderAt       time.Time     `json:"orderAt"`
-	PaymentAt     null.Time     `json:"paymentAt"`
-	CompletedAt   null.Time     `json:"completedAt"`
-	CreatedBy     uuid.UUID     `json:"createdBy"`
-	MetaCreatedAt time.Time     `json:"metaCreatedAt"`
-	UpdatedBy     uuid.UUID     `json:"updatedBy"`
-	MetaUpdatedAt time.Time     `json:"metaUpdatedAt"`
-	DeletedBy     uuid.NullUUID `json:"deletedBy"`
-	MetaDeletedAt null.Time     `json:"metaDeletedAt"`
+	ID              uuid.UUID             `json:"id"`
+	UserID          uuid.UUID             `json:"userId"`
+	PaymentID       uuid.NullUUID         `json:"paymentId,omitempty"`
+	TotalPrice      money.Amount          `json:"totalPrice"`
+	DiscountTotal   money.Amount          `json:"discountTotal"`
+	TaxAmount       money.Amount          `json:"taxAmount"`
+	ShippingMethod  string                `json:"shippingMethod"`
+	ShippingAmount  money.Amount          `json:"shippingAmount"`
+	ShippingAddress model.ShippingAddress `json:"shippingAddress"`
+	Currency        money.Currency        `json:"currency"`
+	Conversions     money.Conversions     `json:"conversions"`
+	Status          int                   `json:"status"`
+	StatusName      string                `json:"statusName"`
+	OrderAt         time.Time             `json:"orderAt"`
+	PaymentAt       null.Time             `json:"paymentAt"`
+	CompletedAt     null.Time             `json:"completedAt"`
+	CreatedBy       uuid.UUID             `json:"createdBy"`
+	MetaCreatedAt   time.Time             `json:"metaCreatedAt"`
+	UpdatedBy       uuid.UUID             `json:"updatedBy"`
+	MetaUpdatedAt   time.Time             `json:"metaUpdatedAt"`
+	DeletedBy       uuid.NullUUID         `json:"deletedBy"`
+	MetaDeletedAt   null.Time             `json:"metaDeletedAt"`
 }
 
 type OrderDetailResponse struct {
 	ID                   uuid.UUID     `json:"id"`
 	OrderID              uuid.UUID     `json:"orderId"`
 	ProductID            uuid.UUID     `json:"productId"`
+	ProductName          string        `json:"productName"`
+	CategoryID           uuid.UUID     `json:"categoryId"`
+	CategoryName         string        `json:"categoryName"`
+	UnitPrice            money.Amount  `json:"unitPrice"`
 	TotalItems           int           `json:"totalItems"`
-	SubtotalProductPrice float64       `json:"subtotalProductPrice"`
+	SubtotalProductPrice money.Amount  `json:"subtotalProductPrice"`
+	TaxRate              int           `json:"taxRate"`
+	TaxInclusive         bool          `json:"taxInclusive"`
+	TaxAmount            money.Amount  `json:"taxAmount"`
 	CreatedBy            uuid.UUID     `json:"createdBy"`
 	MetaCreatedAt        time.Time     `json:"metaCreatedAt"`
 	UpdatedBy            uuid.UUID     `json:"updatedBy"`
@@ -40,15 +57,15 @@ type OrderDetailResponse struct {
 }
 
 type CreateOrderReqeust struct {
-	UserID     string  `json:"userId"`
-	TotalPrice float64 `json:"totalPrice"`
+	UserID     string       `json:"userId"`
+	TotalPrice money.Amount `json:"totalPrice"`
 }
 
 type CreateOrderDetailRequest struct {
-	OrderID              string  `json:"orderId"`
-	ProductID            string  `json:"productId"`
-	TotalItems           int     `json:"totalItems"`
-	SubtotalProductPrice float64 `json:"subtotalProductPrice"`
+	OrderID              string       `json:"orderId"`
+	ProductID            string       `json:"productId"`
+	TotalItems           int          `json:"totalItems"`
+	SubtotalProductPrice money.Amount `json:"subtotalProductPrice"`
 }
 
 func (d *CreateOrderDetailRequest) ToModel() (res model.OrderDetail, err error) {
@@ -98,20 +115,28 @@ func (d *CreateOrderReqeust) ToModel() (res model.Order, err error) {
 
 func NewOrderResponse(order model.Order) OrderResponse {
 	return OrderResponse{
-		ID:            order.ID,
-		UserID:        order.UserID,
-		PaymentID:     order.PaymentID,
-		TotalPrice:    order.TotalPrice,
-		Status:        order.Status,
-		OrderAt:       order.OrderAt,
-		PaymentAt:     order.PaymentAt,
-		CompletedAt:   order.CompletedAt,
-		CreatedBy:     order.CreatedBy,
-		MetaCreatedAt: order.MetaCreatedAt,
-		UpdatedBy:     order.UpdatedBy,
-		MetaUpdatedAt: order.MetaUpdatedAt,
-		DeletedBy:     order.DeletedBy,
-		MetaDeletedAt: order.MetaDeletedAt,
+		ID:              order.ID,
+		UserID:          order.UserID,
+		PaymentID:       order.PaymentID,
+		TotalPrice:      order.TotalPrice,
+		DiscountTotal:   order.DiscountTotal,
+		TaxAmount:       order.TaxAmount,
+		ShippingMethod:  order.ShippingMethod,
+		ShippingAmount:  order.ShippingAmount,
+		ShippingAddress: order.ShippingAddress,
+		Currency:        order.Currency,
+		Conversions:     order.Conversions,
+		Status:          order.Status,
+		StatusName:      model.OrderStatus(order.Status).String(),
+		OrderAt:         order.OrderAt,
+		PaymentAt:       order.PaymentAt,
+		CompletedAt:     order.CompletedAt,
+		CreatedBy:       order.CreatedBy,
+		MetaCreatedAt:   order.MetaCreatedAt,
+		UpdatedBy:       order.UpdatedBy,
+		MetaUpdatedAt:   order.MetaUpdatedAt,
+		DeletedBy:       order.DeletedBy,
+		MetaDeletedAt:   order.MetaDeletedAt,
 	}
 }
 
@@ -120,8 +145,15 @@ func NewOrderDetailResponse(orderDetail model.OrderDetail) OrderDetailResponse {
 		ID:                   orderDetail.ID,
 		OrderID:              orderDetail.OrderID,
 		ProductID:            orderDetail.ProductID,
+		ProductName:          orderDetail.ProductName,
+		CategoryID:           orderDetail.CategoryID,
+		CategoryName:         orderDetail.CategoryName,
+		UnitPrice:            orderDetail.UnitPrice,
 		TotalItems:           orderDetail.TotalItems,
 		SubtotalProductPrice: orderDetail.SubtotalProductPrice,
+		TaxRate:              orderDetail.TaxRate,
+		TaxInclusive:         orderDetail.TaxInclusive,
+		TaxAmount:            orderDetail.TaxAmount,
 		CreatedBy:            orderDetail.CreatedBy,
 		MetaCreatedAt:        orderDetail.MetaCreatedAt,
 		UpdatedBy:            orderDetail.UpdatedBy,
@@ -130,3 +162,122 @@ func NewOrderDetailResponse(orderDetail model.OrderDetail) OrderDetailResponse {
 		MetaDeletedAt:        orderDetail.MetaDeletedAt,
 	}
 }
+
+type OrderStatusHistoryResponse struct {
+	FromStatus     null.Int    `json:"fromStatus"`
+	FromStatusName null.String `json:"fromStatusName"`
+	ToStatus       int         `json:"toStatus"`
+	ToStatusName   string      `json:"toStatusName"`
+	CreatedBy      uuid.UUID   `json:"createdBy"`
+	MetaCreatedAt  time.Time   `json:"metaCreatedAt"`
+}
+
+func NewOrderStatusHistoryResponse(history model.OrderStatusHistory) OrderStatusHistoryResponse {
+	var fromStatusName null.String
+	if history.FromStatus.Valid {
+		fromStatusName = null.StringFrom(model.OrderStatus(history.FromStatus.Int64).String())
+	}
+	return OrderStatusHistoryResponse{
+		FromStatus:     history.FromStatus,
+		FromStatusName: fromStatusName,
+		ToStatus:       history.ToStatus,
+		ToStatusName:   model.OrderStatus(history.ToStatus).String(),
+		CreatedBy:      history.CreatedBy,
+		MetaCreatedAt:  history.MetaCreatedAt,
+	}
+}
+
+type OrderDiscountResponse struct {
+	PromotionID  uuid.UUID    `json:"promotionId"`
+	Code         null.String  `json:"code"`
+	Name         string       `json:"name"`
+	Type         string       `json:"type"`
+	Amount       money.Amount `json:"amount"`
+	FreeShipping bool         `json:"freeShipping"`
+}
+
+func NewOrderDiscountResponse(discount model.OrderDiscount) OrderDiscountResponse {
+	return OrderDiscountResponse{
+		PromotionID:  discount.PromotionID,
+		Code:         discount.Code,
+		Name:         discount.Name,
+		Type:         promotionModel.PromotionType(discount.Type).String(),
+		Amount:       discount.Amount,
+		FreeShipping: discount.FreeShipping,
+	}
+}
+
+type OrderWithDetailsResponse struct {
+	Order         OrderResponse                `json:"order"`
+	Details       []OrderDetailResponse        `json:"details"`
+	Discounts     []OrderDiscountResponse      `json:"discounts"`
+	StatusHistory []OrderStatusHistoryResponse `json:"statusHistory"`
+}
+
+func NewOrderWithDetailsResponse(order model.Order, details []model.OrderDetail, discounts []model.OrderDiscount, history []model.OrderStatusHistory) OrderWithDetailsResponse {
+	detailsRes := make([]OrderDetailResponse, 0, len(details))
+	for _, v := range details {
+		detailsRes = append(detailsRes, NewOrderDetailResponse(v))
+	}
+	discountsRes := make([]OrderDiscountResponse, 0, len(discounts))
+	for _, v := range discounts {
+		discountsRes = append(discountsRes, NewOrderDiscountResponse(v))
+	}
+	historyRes := make([]OrderStatusHistoryResponse, 0, len(history))
+	for _, v := range history {
+		historyRes = append(historyRes, NewOrderStatusHistoryResponse(v))
+	}
+	return OrderWithDetailsResponse{
+		Order:         NewOrderResponse(order),
+		Details:       detailsRes,
+		Discounts:     discountsRes,
+		StatusHistory: historyRes,
+	}
+}
+
+type ListOrdersRequest struct {
+	Page     int `query:"page"`
+	PageSize int `query:"pageSize"`
+}
+
+const maxOrderPageSize = 100
+
+func (d *ListOrdersRequest) SetDefault() {
+	if d.Page <= 0 {
+		d.Page = 1
+	}
+	if d.PageSize <= 0 {
+		d.PageSize = 10
+	}
+	if d.PageSize > maxOrderPageSize {
+		d.PageSize = maxOrderPageSize
+	}
+}
+
+type Metadata struct {
+	Page      int `json:"page"`
+	PageSize  int `json:"pageSize"`
+	TotalData int `json:"totalData"`
+	TotalPage int `json:"totalPage"`
+}
+
+type OrderListResponse struct {
+	Data     []OrderResponse `json:"data"`
+	Metadata Metadata        `json:"metadata"`
+}
+
+func NewOrderListResponse(orders []model.Order, page, pageSize, totalData int) OrderListResponse {
+	data := make([]OrderResponse, 0, len(orders))
+	for _, v := range orders {
+		data = append(data, NewOrderResponse(v))
+	}
+	return OrderListResponse{
+		Data: data,
+		Metadata: Metadata{
+			Page:      page,
+			PageSize:  pageSize,
+			TotalData: totalData,
+			TotalPage: (totalData + pageSize - 1) / pageSize,
+		},
+	}
+}
@@ -1,8 +1,11 @@
 package model
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
 	"github.com/gofrs/uuid"
 	"github.com/guregu/null"
 )
@@ -10,23 +13,106 @@ import (
 type OrderStatus int
 
 var (
-	OrderPlacedStatus OrderStatus = 0
-	OrderPaidStatus   OrderStatus = 1
+	OrderPlacedStatus    OrderStatus = 0
+	OrderPaidStatus      OrderStatus = 1
+	OrderShippedStatus   OrderStatus = 2
+	OrderDeliveredStatus OrderStatus = 3
+	OrderCancelledStatus OrderStatus = 4
+	OrderExpiredStatus   OrderStatus = 5
+	OrderRefundedStatus  OrderStatus = 6
 )
 
+// SystemUserID is recorded as the actor of changes made by background jobs.
+var SystemUserID = uuid.Nil
+
+// orderTransitions lists the statuses each status may move to. Statuses
+// missing from the map are terminal.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderPlacedStatus:    {OrderPaidStatus, OrderCancelledStatus, OrderExpiredStatus},
+	OrderPaidStatus:      {OrderShippedStatus, OrderCancelledStatus, OrderRefundedStatus},
+	OrderShippedStatus:   {OrderDeliveredStatus},
+	OrderDeliveredStatus: {OrderRefundedStatus},
+}
+
+func (s OrderStatus) String() string {
+	switch s {
+	case OrderPlacedStatus:
+		return "placed"
+	case OrderPaidStatus:
+		return "paid"
+	case OrderShippedStatus:
+		return "shipped"
+	case OrderDeliveredStatus:
+		return "delivered"
+	case OrderCancelledStatus:
+		return "cancelled"
+	case OrderExpiredStatus:
+		return "expired"
+	case OrderRefundedStatus:
+		return "refunded"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// CanTransitionTo reports whether an order may move from s to next.
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 type Order struct {
-	ID            uuid.UUID     `db:"id"`
-	UserID        uuid.UUID     `db:"user_id"`
-	PaymentID     uuid.NullUUID `db:"payment_id"`
-	TotalPrice    float64       `db:"total_price"`
-	Status        int           `db:"status"`
-	OrderAt       time.Time     `db:"order_at"`
-	PaymentAt     null.Time     `db:"payment_at"`
-	CompletedAt   null.Time     `db:"completed_at"`
-	CreatedBy     uuid.UUID     `db:"created_by"`
-	MetaCreatedAt time.Time     `db:"meta_created_at"`
-	UpdatedBy     uuid.UUID     `db:"updated_by"`
-	MetaUpdatedAt time.Time     `db:"meta_updated_at"`
-	DeletedBy     uuid.NullUUID `db:"deleted_by"`
-	MetaDeletedAt null.Time     `db:"meta_deleted_at"`
+	ID         uuid.UUID     `db:"id"`
+	UserID     uuid.UUID     `db:"user_id"`
+	PaymentID  uuid.NullUUID `db:"payment_id"`
+	TotalPrice money.Amount  `db:"total_price"`
+	// DiscountTotal is what promotions took off, TotalPrice is net of it.
+	DiscountTotal money.Amount `db:"discount_total"`
+	// TaxAmount is the tax of the details, TotalPrice includes it.
+	TaxAmount money.Amount `db:"tax_amount"`
+	// ShippingAmount is what ShippingMethod costs to ShippingAddress,
+	// TotalPrice includes it.
+	ShippingMethod  string          `db:"shipping_method"`
+	ShippingAmount  money.Amount    `db:"shipping_amount"`
+	ShippingAddress ShippingAddress `db:"shipping_address"`
+	Currency        money.Currency  `db:"currency"`
+	// Conversions are the exchange rates the total was priced with.
+	Conversions   money.Conversions `db:"conversion_snapshot"`
+	Status        int               `db:"status"`
+	OrderAt       time.Time         `db:"order_at"`
+	PaymentAt     null.Time         `db:"payment_at"`
+	CompletedAt   null.Time         `db:"completed_at"`
+	CreatedBy     uuid.UUID         `db:"created_by"`
+	MetaCreatedAt time.Time         `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID         `db:"updated_by"`
+	MetaUpdatedAt time.Time         `db:"meta_updated_at"`
+	DeletedBy     uuid.NullUUID     `db:"deleted_by"`
+	MetaDeletedAt null.Time         `db:"meta_deleted_at"`
+}
+
+// TransitionTo moves the order to next and returns the history entry that
+// records the move. Illegal moves are rejected with a conflict and leave the
+// order untouched.
+func (m *Order) TransitionTo(next OrderStatus, by uuid.UUID) (history OrderStatusHistory, err error) {
+	current := OrderStatus(m.Status)
+	if !current.CanTransitionTo(next) {
+		err = failure.Conflict("transition", "order", fmt.Sprintf("cannot move from %s to %s", current, next))
+		return
+	}
+
+	now := time.Now()
+	switch next {
+	case OrderPaidStatus:
+		m.PaymentAt = null.TimeFrom(now)
+	case OrderDeliveredStatus:
+		m.CompletedAt = null.TimeFrom(now)
+	}
+	m.Status = int(next)
+	m.UpdatedBy = by
+
+	return NewOrderStatusHistory(m.ID, null.IntFrom(int64(current)), next, by), nil
 }
@@ -3,20 +3,34 @@ package model
 import (
 	"time"
 
+	"github.com/azka-zaydan/synapsis-test/shared/money"
 	"github.com/gofrs/uuid"
 	"github.com/guregu/null"
 )
 
+// OrderDetail is a line of an order. The product and category fields are a
+// copy of the catalog taken at checkout, so later changes to it do not
+// change the order.
 type OrderDetail struct {
-	ID                   uuid.UUID     `db:"id"`
-	OrderID              uuid.UUID     `db:"order_id"`
-	ProductID            uuid.UUID     `db:"product_id"`
-	TotalItems           int           `db:"total_items"`
-	SubtotalProductPrice float64       `db:"subtotal_product_price"`
-	CreatedBy            uuid.UUID     `db:"created_by"`
-	MetaCreatedAt        time.Time     `db:"meta_created_at"`
-	UpdatedBy            uuid.UUID     `db:"updated_by"`
-	MetaUpdatedAt        time.Time     `db:"meta_updated_at"`
-	DeletedBy            uuid.NullUUID `db:"deleted_by"`
-	MetaDeletedAt        null.Time     `db:"meta_deleted_at"`
+	ID           uuid.UUID    `db:"id"`
+	OrderID      uuid.UUID    `db:"order_id"`
+	ProductID    uuid.UUID    `db:"product_id"`
+	ProductName  string       `db:"product_name"`
+	CategoryID   uuid.UUID    `db:"category_id"`
+	CategoryName string       `db:"category_name"`
+	UnitPrice    money.Amount `db:"unit_price"`
+	TotalItems   int          `db:"total_items"`
+	// SubtotalProductPrice is UnitPrice times TotalItems.
+	SubtotalProductPrice money.Amount `db:"subtotal_product_price"`
+	// TaxRate is in basis points. Inclusive tax is part of the subtotal,
+	// exclusive tax is added to the order on top of it.
+	TaxRate       int           `db:"tax_rate"`
+	TaxInclusive  bool          `db:"tax_inclusive"`
+	TaxAmount     money.Amount  `db:"tax_amount"`
+	CreatedBy     uuid.UUID     `db:"created_by"`
+	MetaCreatedAt time.Time     `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID     `db:"updated_by"`
+	MetaUpdatedAt time.Time     `db:"meta_updated_at"`
+	DeletedBy     uuid.NullUUID `db:"deleted_by"`
+	MetaDeletedAt null.Time     `db:"meta_deleted_at"`
 }
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+// OrderDiscount is a promotion applied to an order, kept as it was when the
+// order was placed.
+type OrderDiscount struct {
+	ID            uuid.UUID     `db:"id"`
+	OrderID       uuid.UUID     `db:"order_id"`
+	PromotionID   uuid.UUID     `db:"promotion_id"`
+	Code          null.String   `db:"code"`
+	Name          string        `db:"name"`
+	Type          int           `db:"type"`
+	Amount        money.Amount  `db:"amount"`
+	FreeShipping  bool          `db:"free_shipping"`
+	CreatedBy     uuid.UUID     `db:"created_by"`
+	MetaCreatedAt time.Time     `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID     `db:"updated_by"`
+	MetaUpdatedAt time.Time     `db:"meta_updated_at"`
+	DeletedBy     uuid.NullUUID `db:"deleted_by"`
+	MetaDeletedAt null.Time     `db:"meta_deleted_at"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+// OrderStatusHistory records a single status change of an order. FromStatus
+// is empty for the entry written when the order is placed.
+type OrderStatusHistory struct {
+	ID            uuid.UUID     `db:"id"`
+	OrderID       uuid.UUID     `db:"order_id"`
+	FromStatus    null.Int      `db:"from_status"`
+	ToStatus      int           `db:"to_status"`
+	CreatedBy     uuid.UUID     `db:"created_by"`
+	MetaCreatedAt time.Time     `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID     `db:"updated_by"`
+	MetaUpdatedAt time.Time     `db:"meta_updated_at"`
+	DeletedBy     uuid.NullUUID `db:"deleted_by"`
+	MetaDeletedAt null.Time     `db:"meta_deleted_at"`
+}
+
+func NewOrderStatusHistory(orderID uuid.UUID, from null.Int, to OrderStatus, by uuid.UUID) OrderStatusHistory {
+	id, _ := uuid.NewV4()
+	return OrderStatusHistory{
+		ID:         id,
+		OrderID:    orderID,
+		FromStatus: from,
+		ToStatus:   int(to),
+		CreatedBy:  by,
+		UpdatedBy:  by,
+	}
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// ShippingAddress is the copy of the address an order ships to, taken at
+// checkout so later edits of the address book do not change the order.
+// It is stored as JSON.
+type ShippingAddress struct {
+	AddressID     string `json:"addressId"`
+	RecipientName string `json:"recipientName"`
+	Phone         string `json:"phone"`
+	Line1         string `json:"line1"`
+	Line2         string `json:"line2"`
+	City          string `json:"city"`
+	Region        string `json:"region"`
+	PostalCode    string `json:"postalCode"`
+	Country       string `json:"country"`
+}
+
+// Scan implements sql.Scanner for JSON columns. NULL scans as empty.
+func (a *ShippingAddress) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = ShippingAddress{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return fmt.Errorf("order: cannot scan %T into shipping address", src)
+	}
+}
+
+// Value implements driver.Valuer, writing the address as JSON.
+func (a ShippingAddress) Value() (driver.Value, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
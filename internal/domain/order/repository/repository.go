@@ -3,10 +3,13 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/azka-zaydan/synapsis-test/infras"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/order/model"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
 	"github.com/azka-zaydan/synapsis-test/shared/logger"
+	"github.com/jmoiron/sqlx"
 )
 
 type OrderRepository interface {
@@ -15,7 +18,19 @@ type OrderRepository interface {
 	GetOrderByID(ctx context.Context, orderId string) (res model.Order, err error)
 	UpdateOrder(ctx context.Context, order *model.Order) (err error)
 	UpdateOrderDetail(ctx context.Context, order *model.OrderDetail) (err error)
-	GetOrderDetailByID(ctx context.Context, orderDetailId string) (res model.Order, err error)
+	GetOrderDetailByID(ctx context.Context, orderDetailId string) (res model.OrderDetail, err error)
+	GetOrdersByUserID(ctx context.Context, userId string, page, pageSize int) (res []model.Order, totalData int, err error)
+	GetOrderDetailsByOrderID(ctx context.Context, orderId string) (res []model.OrderDetail, err error)
+	GetOrderStatusHistoryByOrderID(ctx context.Context, orderId string) (res []model.OrderStatusHistory, err error)
+	CreateOrderTx(ctx context.Context, tx *sqlx.Tx, order *model.Order) (err error)
+	CreateOrderDetailTx(ctx context.Context, tx *sqlx.Tx, detail *model.OrderDetail) (err error)
+	UpdateOrderTx(ctx context.Context, tx *sqlx.Tx, order *model.Order) (err error)
+	UpdateOrderStatusTx(ctx context.Context, tx *sqlx.Tx, order *model.Order, history *model.OrderStatusHistory) (err error)
+	CreateOrderStatusHistoryTx(ctx context.Context, tx *sqlx.Tx, history *model.OrderStatusHistory) (err error)
+	GetOrderByIDForUpdate(ctx context.Context, tx *sqlx.Tx, orderId string) (res model.Order, err error)
+	GetUnpaidOrdersBeforeForUpdate(ctx context.Context, tx *sqlx.Tx, orderedBefore time.Time, limit int) (res []model.Order, err error)
+	CreateOrderDiscountTx(ctx context.Context, tx *sqlx.Tx, discount *model.OrderDiscount) (err error)
+	GetOrderDiscountsByOrderID(ctx context.Context, orderId string) (res []model.OrderDiscount, err error)
 }
 
 type OrderRepositoryMySQL struct {
@@ -73,7 +88,7 @@ func (repo *OrderRepositoryMySQL) UpdateOrderDetail(ctx context.Context, order *
 	return
 }
 
-func (repo *OrderRepositoryMySQL) GetOrderDetailByID(ctx context.Context, orderDetailId string) (res model.Order, err error) {
+func (repo *OrderRepositoryMySQL) GetOrderDetailByID(ctx context.Context, orderDetailId string) (res model.OrderDetail, err error) {
 	err = repo.DB.Read.GetContext(ctx, &res, fmt.Sprintf("%s WHERE id = ?", orderDetailSelectQuery), orderDetailId)
 	if err != nil {
 		logger.ErrorWithStack(err)
@@ -82,42 +97,265 @@ func (repo *OrderRepositoryMySQL) GetOrderDetailByID(ctx context.Context, orderD
 	return
 }
 
+func (repo *OrderRepositoryMySQL) GetOrdersByUserID(ctx context.Context, userId string, page, pageSize int) (res []model.Order, totalData int, err error) {
+	err = repo.DB.Read.GetContext(ctx, &totalData, fmt.Sprintf("%s WHERE user_id = ?", countOrderQuery), userId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+
+	offset := (page - 1) * pageSize
+	query := fmt.Sprintf("%s WHERE user_id = ? ORDER BY order_at DESC, id DESC LIMIT %d OFFSET %d", orderSelectQuery, pageSize, offset)
+	err = repo.DB.Read.SelectContext(ctx, &res, query, userId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *OrderRepositoryMySQL) GetOrderDetailsByOrderID(ctx context.Context, orderId string) (res []model.OrderDetail, err error) {
+	err = repo.DB.Read.SelectContext(ctx, &res, fmt.Sprintf("%s WHERE order_id = ?", orderDetailSelectQuery), orderId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *OrderRepositoryMySQL) GetOrderStatusHistoryByOrderID(ctx context.Context, orderId string) (res []model.OrderStatusHistory, err error) {
+	err = repo.DB.Read.SelectContext(ctx, &res, fmt.Sprintf("%s WHERE order_id = ? ORDER BY meta_created_at", orderStatusHistorySelectQuery), orderId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *OrderRepositoryMySQL) CreateOrderTx(ctx context.Context, tx *sqlx.Tx, order *model.Order) (err error) {
+	_, err = tx.NamedExecContext(ctx, orderInsertQuery, order)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *OrderRepositoryMySQL) CreateOrderDetailTx(ctx context.Context, tx *sqlx.Tx, detail *model.OrderDetail) (err error) {
+	_, err = tx.NamedExecContext(ctx, orderDetailInsertQuery, detail)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *OrderRepositoryMySQL) UpdateOrderTx(ctx context.Context, tx *sqlx.Tx, order *model.Order) (err error) {
+	_, err = tx.NamedExecContext(ctx, orderUpdateQuery, order)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+// UpdateOrderStatusTx saves a transition made with Order.TransitionTo. The
+// update only applies while the order still has the status the transition
+// started from, so two racing transitions cannot both succeed.
+func (repo *OrderRepositoryMySQL) UpdateOrderStatusTx(ctx context.Context, tx *sqlx.Tx, order *model.Order, history *model.OrderStatusHistory) (err error) {
+	result, err := tx.ExecContext(ctx, orderStatusUpdateQuery, order.Status, order.PaymentAt, order.CompletedAt, order.UpdatedBy, order.ID, history.FromStatus.Int64)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	if affected == 0 {
+		err = failure.Conflict("transition", "order", "status was changed by another request")
+		return
+	}
+	return repo.CreateOrderStatusHistoryTx(ctx, tx, history)
+}
+
+func (repo *OrderRepositoryMySQL) CreateOrderStatusHistoryTx(ctx context.Context, tx *sqlx.Tx, history *model.OrderStatusHistory) (err error) {
+	_, err = tx.NamedExecContext(ctx, orderStatusHistoryInsertQuery, history)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *OrderRepositoryMySQL) GetOrderByIDForUpdate(ctx context.Context, tx *sqlx.Tx, orderId string) (res model.Order, err error) {
+	err = tx.GetContext(ctx, &res, fmt.Sprintf("%s WHERE id = ? FOR UPDATE", orderSelectQuery), orderId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *OrderRepositoryMySQL) GetUnpaidOrdersBeforeForUpdate(ctx context.Context, tx *sqlx.Tx, orderedBefore time.Time, limit int) (res []model.Order, err error) {
+	query := fmt.Sprintf("%s WHERE status = ? AND order_at <= ? ORDER BY order_at LIMIT %d FOR UPDATE SKIP LOCKED", orderSelectQuery, limit)
+	err = tx.SelectContext(ctx, &res, query, model.OrderPlacedStatus, orderedBefore)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *OrderRepositoryMySQL) CreateOrderDiscountTx(ctx context.Context, tx *sqlx.Tx, discount *model.OrderDiscount) (err error) {
+	_, err = tx.NamedExecContext(ctx, orderDiscountInsertQuery, discount)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *OrderRepositoryMySQL) GetOrderDiscountsByOrderID(ctx context.Context, orderId string) (res []model.OrderDiscount, err error) {
+	err = repo.DB.Read.SelectContext(ctx, &res, fmt.Sprintf("%s WHERE order_id = ? ORDER BY meta_created_at, id", orderDiscountSelectQuery), orderId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
 var (
-	orderInsertQuery = "INSERT INTO `order` (id,user_id,payment_id,total_price,status,order_at,payment_at,completed_at,created_by,updated_by) VALUES (:id,:user_id,:payment_id,:total_price,:status,:order_at,:payment_at,:completed_at,:created_by,:updated_by)"
+	orderInsertQuery = "INSERT INTO `order` (id,user_id,payment_id,total_price,discount_total,tax_amount,shipping_method,shipping_amount,shipping_address,currency,conversion_snapshot,status,order_at,payment_at,completed_at,created_by,updated_by) VALUES (:id,:user_id,:payment_id,:total_price,:discount_total,:tax_amount,:shipping_method,:shipping_amount,:shipping_address,:currency,:conversion_snapshot,:status,:order_at,:payment_at,:completed_at,:created_by,:updated_by)"
 
 	orderDetailInsertQuery = `
 	INSERT INTO order_detail (
 		id,
 		order_id,
 		product_id,
+		product_name,
+		category_id,
+		category_name,
+		unit_price,
 		total_items,
 		subtotal_product_price,
+		tax_rate,
+		tax_inclusive,
+		tax_amount,
 		created_by,
 		updated_by
 	) VALUES (
 		:id,
 		:order_id,
 		:product_id,
+		:product_name,
+		:category_id,
+		:category_name,
+		:unit_price,
 		:total_items,
 		:subtotal_product_price,
+		:tax_rate,
+		:tax_inclusive,
+		:tax_amount,
 		:created_by,
 		:updated_by
 	)`
 
-	orderSelectQuery       = "SELECT id, user_id, payment_id, total_price, status, order_at, payment_at, completed_at, created_by, updated_by FROM `order`"
+	orderSelectQuery       = "SELECT id, user_id, payment_id, total_price, discount_total, tax_amount, shipping_method, shipping_amount, shipping_address, currency, conversion_snapshot, status, order_at, payment_at, completed_at, created_by, meta_created_at, updated_by, meta_updated_at FROM `order`"
+	countOrderQuery        = "SELECT COUNT(id) FROM `order`"
 	orderDetailSelectQuery = `
 	SELECT
 		id,
 		order_id,
 		product_id,
+		product_name,
+		category_id,
+		category_name,
+		unit_price,
 		total_items,
 		subtotal_product_price,
+		tax_rate,
+		tax_inclusive,
+		tax_amount,
 		created_by,
-		updated_by
+		meta_created_at,
+		updated_by,
+		meta_updated_at
 	FROM order_detail
 `
+	orderStatusHistorySelectQuery = `
+	SELECT
+		id,
+		order_id,
+		from_status,
+		to_status,
+		created_by,
+		meta_created_at,
+		updated_by,
+		meta_updated_at
+	FROM order_status_history`
 	orderUpdateQuery = "UPDATE `order` SET user_id = :user_id, payment_id = :payment_id, total_price = :total_price, status = :status, order_at = :order_at, payment_at = :payment_at, completed_at = :completed_at, updated_by = :updated_by WHERE id = :id"
 
+	orderStatusUpdateQuery = "UPDATE `order` SET status = ?, payment_at = ?, completed_at = ?, updated_by = ? WHERE id = ? AND status = ?"
+
+	orderStatusHistoryInsertQuery = `
+	INSERT INTO order_status_history (
+		id,
+		order_id,
+		from_status,
+		to_status,
+		created_by,
+		updated_by
+	) VALUES (
+		:id,
+		:order_id,
+		:from_status,
+		:to_status,
+		:created_by,
+		:updated_by
+	)`
+
+	orderDiscountInsertQuery = `
+	INSERT INTO order_discount (
+		id,
+		order_id,
+		promotion_id,
+		code,
+		name,
+		type,
+		amount,
+		free_shipping,
+		created_by,
+		updated_by
+	) VALUES (
+		:id,
+		:order_id,
+		:promotion_id,
+		:code,
+		:name,
+		:type,
+		:amount,
+		:free_shipping,
+		:created_by,
+		:updated_by
+	)`
+	orderDiscountSelectQuery = `
+	SELECT
+		id,
+		order_id,
+		promotion_id,
+		code,
+		name,
+		type,
+		amount,
+		free_shipping,
+		created_by,
+		meta_created_at,
+		updated_by,
+		meta_updated_at
+	FROM order_discount`
+
 	orderDetailUpdateQuery = `
 	UPDATE order_detail SET
 		order_id = :order_id,
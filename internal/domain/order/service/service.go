@@ -1,23 +1,349 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"time"
+
 	"github.com/azka-zaydan/synapsis-test/configs"
 	"github.com/azka-zaydan/synapsis-test/infras"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/order/model"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/order/model/dto"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/order/repository"
+	paymentModel "github.com/azka-zaydan/synapsis-test/internal/domain/payment/model"
+	paymentRepo "github.com/azka-zaydan/synapsis-test/internal/domain/payment/repository"
+	productRepo "github.com/azka-zaydan/synapsis-test/internal/domain/product/repository"
+	reservationModel "github.com/azka-zaydan/synapsis-test/internal/domain/reservation/model"
+	reservationRepo "github.com/azka-zaydan/synapsis-test/internal/domain/reservation/repository"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// expiryBatchSize caps how many orders a single expiry sweep handles.
+	expiryBatchSize       = 100
+	defaultPaymentTimeout = 15 * time.Minute
 )
 
-type OrderService interface{}
+type OrderService interface {
+	ListOrders(ctx context.Context, req dto.ListOrdersRequest, userID uuid.UUID) (res dto.OrderListResponse, err error)
+	GetOrder(ctx context.Context, orderID string, userID uuid.UUID) (res dto.OrderWithDetailsResponse, err error)
+	CancelOrder(ctx context.Context, orderID string, userID uuid.UUID) (res dto.OrderResponse, err error)
+	ShipOrder(ctx context.Context, orderID string, adminID uuid.UUID) (res dto.OrderResponse, err error)
+	DeliverOrder(ctx context.Context, orderID string, adminID uuid.UUID) (res dto.OrderResponse, err error)
+	ExpireUnpaidOrders(ctx context.Context) (expired int, err error)
+}
 
 type OrderServiceImpl struct {
-	Repo   repository.OrderRepository
-	Redis  *infras.Redis
-	config *configs.Config
+	Repo            repository.OrderRepository
+	DB              *infras.MySQLConn
+	Redis           *infras.Redis
+	config          *configs.Config
+	PaymentRepo     paymentRepo.PaymentRepository
+	ProductRepo     productRepo.ProductRepository
+	ReservationRepo reservationRepo.ReservationRepository
 }
 
-func ProvideOrderServiceImpl(repo repository.OrderRepository, redis *infras.Redis, config *configs.Config) *OrderServiceImpl {
+func ProvideOrderServiceImpl(repo repository.OrderRepository, db *infras.MySQLConn, redis *infras.Redis, config *configs.Config, paymentRepo paymentRepo.PaymentRepository, productRepo productRepo.ProductRepository, reservationRepo reservationRepo.ReservationRepository) *OrderServiceImpl {
 	return &OrderServiceImpl{
-		Redis:  redis,
-		Repo:   repo,
-		config: config,
+		DB:              db,
+		Redis:           redis,
+		Repo:            repo,
+		config:          config,
+		PaymentRepo:     paymentRepo,
+		ProductRepo:     productRepo,
+		ReservationRepo: reservationRepo,
+	}
+}
+
+func (s *OrderServiceImpl) ListOrders(ctx context.Context, req dto.ListOrdersRequest, userID uuid.UUID) (res dto.OrderListResponse, err error) {
+	req.SetDefault()
+	orders, totalData, err := s.Repo.GetOrdersByUserID(ctx, userID.String(), req.Page, req.PageSize)
+	if err != nil {
+		log.Error().Err(err).Msg("[ListOrders] Failed GetOrdersByUserID")
+		return
+	}
+	return dto.NewOrderListResponse(orders, req.Page, req.PageSize, totalData), nil
+}
+
+func (s *OrderServiceImpl) GetOrder(ctx context.Context, orderID string, userID uuid.UUID) (res dto.OrderWithDetailsResponse, err error) {
+	order, err := s.Repo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = failure.NotFound("order")
+		}
+		log.Error().Err(err).Msg("[GetOrder] Failed GetOrderByID")
+		return
+	}
+	// other users' orders are reported as missing rather than forbidden
+	if order.UserID != userID {
+		err = failure.NotFound("order")
+		return
+	}
+
+	details, err := s.Repo.GetOrderDetailsByOrderID(ctx, orderID)
+	if err != nil {
+		log.Error().Err(err).Msg("[GetOrder] Failed GetOrderDetailsByOrderID")
+		return
+	}
+	discounts, err := s.Repo.GetOrderDiscountsByOrderID(ctx, orderID)
+	if err != nil {
+		log.Error().Err(err).Msg("[GetOrder] Failed GetOrderDiscountsByOrderID")
+		return
+	}
+	history, err := s.Repo.GetOrderStatusHistoryByOrderID(ctx, orderID)
+	if err != nil {
+		log.Error().Err(err).Msg("[GetOrder] Failed GetOrderStatusHistoryByOrderID")
+		return
+	}
+	return dto.NewOrderWithDetailsResponse(order, details, discounts, history), nil
+}
+
+// CancelOrder cancels an order of the user that has not shipped yet. An
+// unpaid payment is cancelled, a paid one is left waiting for a refund, and
+// the ordered stock is put back on sale.
+func (s *OrderServiceImpl) CancelOrder(ctx context.Context, orderID string, userID uuid.UUID) (res dto.OrderResponse, err error) {
+	var order model.Order
+	err = s.DB.WithTransaction(func(tx *sqlx.Tx, e chan error) {
+		var err error
+		order, err = s.Repo.GetOrderByIDForUpdate(ctx, tx, orderID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				err = failure.NotFound("order")
+			}
+			e <- err
+			return
+		}
+		if order.UserID != userID {
+			e <- failure.NotFound("order")
+			return
+		}
+
+		history, err := order.TransitionTo(model.OrderCancelledStatus, userID)
+		if err != nil {
+			e <- err
+			return
+		}
+		err = s.Repo.UpdateOrderStatusTx(ctx, tx, &order, &history)
+		if err != nil {
+			e <- err
+			return
+		}
+
+		payment, err := s.PaymentRepo.GetPaymentByOrderIDForUpdate(ctx, tx, orderID)
+		if err != nil {
+			e <- err
+			return
+		}
+		var restocked map[uuid.UUID]int
+		switch payment.Status {
+		case int(paymentModel.Unpaid), int(paymentModel.Pending):
+			payment.Cancel()
+		case int(paymentModel.Paid), int(paymentModel.PartiallyRefunded):
+			payment.MarkRefundPending()
+			restocked, err = s.restockedByRefunds(ctx, tx, payment.ID.String())
+			if err != nil {
+				e <- err
+				return
+			}
+		}
+		payment.UpdatedBy = userID
+		err = s.PaymentRepo.UpdatePaymentTx(ctx, tx, &payment)
+		if err != nil {
+			e <- err
+			return
+		}
+
+		err = s.restockOrder(ctx, tx, orderID, restocked)
+		if err != nil {
+			e <- err
+			return
+		}
+		e <- nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[CancelOrder] Failed Cancelling Order")
+		return
+	}
+	return dto.NewOrderResponse(order), nil
+}
+
+// ShipOrder marks a paid order as shipped. It can no longer be cancelled.
+func (s *OrderServiceImpl) ShipOrder(ctx context.Context, orderID string, adminID uuid.UUID) (res dto.OrderResponse, err error) {
+	res, err = s.transitionOrder(ctx, orderID, model.OrderShippedStatus, adminID)
+	if err != nil {
+		log.Error().Err(err).Msg("[ShipOrder] Failed transitionOrder")
+		return
+	}
+	return
+}
+
+// DeliverOrder marks a shipped order as delivered, completing it.
+func (s *OrderServiceImpl) DeliverOrder(ctx context.Context, orderID string, adminID uuid.UUID) (res dto.OrderResponse, err error) {
+	res, err = s.transitionOrder(ctx, orderID, model.OrderDeliveredStatus, adminID)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeliverOrder] Failed transitionOrder")
+		return
+	}
+	return
+}
+
+// transitionOrder moves an order of any user to next, recording it in the
+// status history.
+func (s *OrderServiceImpl) transitionOrder(ctx context.Context, orderID string, next model.OrderStatus, by uuid.UUID) (res dto.OrderResponse, err error) {
+	var order model.Order
+	err = s.DB.WithTransaction(func(tx *sqlx.Tx, e chan error) {
+		var err error
+		order, err = s.Repo.GetOrderByIDForUpdate(ctx, tx, orderID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				err = failure.NotFound("order")
+			}
+			e <- err
+			return
+		}
+		history, err := order.TransitionTo(next, by)
+		if err != nil {
+			e <- err
+			return
+		}
+		e <- s.Repo.UpdateOrderStatusTx(ctx, tx, &order, &history)
+	})
+	if err != nil {
+		return
+	}
+	return dto.NewOrderResponse(order), nil
+}
+
+// ExpireUnpaidOrders expires orders left unpaid past the payment timeout,
+// voids their payments and puts their stock back on sale.
+func (s *OrderServiceImpl) ExpireUnpaidOrders(ctx context.Context) (expired int, err error) {
+	timeout := s.config.Order.PaymentTimeout
+	if timeout <= 0 {
+		timeout = defaultPaymentTimeout
+	}
+	err = s.DB.WithTransaction(func(tx *sqlx.Tx, e chan error) {
+		orders, err := s.Repo.GetUnpaidOrdersBeforeForUpdate(ctx, tx, time.Now().Add(-timeout), expiryBatchSize)
+		if err != nil {
+			e <- err
+			return
+		}
+		for i := range orders {
+			err = s.expireOrder(ctx, tx, &orders[i])
+			if err != nil {
+				e <- err
+				return
+			}
+		}
+		expired = len(orders)
+		e <- nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[ExpireUnpaidOrders] Failed Expiring Orders")
+		return 0, err
+	}
+	return
+}
+
+func (s *OrderServiceImpl) expireOrder(ctx context.Context, tx *sqlx.Tx, order *model.Order) (err error) {
+	history, err := order.TransitionTo(model.OrderExpiredStatus, model.SystemUserID)
+	if err != nil {
+		return
+	}
+	err = s.Repo.UpdateOrderStatusTx(ctx, tx, order, &history)
+	if err != nil {
+		return
+	}
+
+	payment, err := s.PaymentRepo.GetPaymentByOrderIDForUpdate(ctx, tx, order.ID.String())
+	if err != nil && err != sql.ErrNoRows {
+		return
+	}
+	if err == nil && (payment.Status == int(paymentModel.Unpaid) || payment.Status == int(paymentModel.Pending)) {
+		payment.Void()
+		payment.UpdatedBy = model.SystemUserID
+		err = s.PaymentRepo.UpdatePaymentTx(ctx, tx, &payment)
+		if err != nil {
+			return
+		}
+	}
+
+	return s.restockOrder(ctx, tx, order.ID.String(), nil)
+}
+
+// restockedByRefunds returns the quantity of each product refunds of the
+// payment already put back in stock.
+func (s *OrderServiceImpl) restockedByRefunds(ctx context.Context, tx *sqlx.Tx, paymentId string) (res map[uuid.UUID]int, err error) {
+	items, err := s.PaymentRepo.GetRestockedRefundItemsByPaymentIDTx(ctx, tx, paymentId)
+	if err != nil {
+		return
+	}
+	res = make(map[uuid.UUID]int)
+	for _, item := range items {
+		res[item.ProductID] += item.Quantity
+	}
+	return
+}
+
+// restockOrder returns the stock taken by an order, less the quantities of
+// restocked that refunds already returned. Stock of holds the reservation
+// sweeper already released is not returned twice. Orders placed before
+// reservations existed are restocked from their details.
+func (s *OrderServiceImpl) restockOrder(ctx context.Context, tx *sqlx.Tx, orderId string, restocked map[uuid.UUID]int) (err error) {
+	reservations, err := s.ReservationRepo.GetReservationsByOrderIDForUpdate(ctx, tx, orderId)
+	if err != nil {
+		return
+	}
+	if len(reservations) == 0 {
+		details, err := s.Repo.GetOrderDetailsByOrderID(ctx, orderId)
+		if err != nil {
+			return err
+		}
+		for _, detail := range details {
+			quantity := unrestocked(restocked, detail.ProductID, detail.TotalItems)
+			if quantity == 0 {
+				continue
+			}
+			err = s.ProductRepo.AdjustProductStockTx(ctx, tx, detail.ProductID.String(), quantity)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for i := range reservations {
+		if reservations[i].Status == int(reservationModel.ReservationReleased) {
+			continue
+		}
+		quantity := unrestocked(restocked, reservations[i].ProductID, reservations[i].Quantity)
+		if quantity > 0 {
+			err = s.ProductRepo.AdjustProductStockTx(ctx, tx, reservations[i].ProductID.String(), quantity)
+			if err != nil {
+				return
+			}
+		}
+		reservations[i].Status = int(reservationModel.ReservationReleased)
+		err = s.ReservationRepo.UpdateReservationTx(ctx, tx, &reservations[i])
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// unrestocked returns how much of quantity of the product is still out of
+// stock, taking it off what restocked has left for the product.
+func unrestocked(restocked map[uuid.UUID]int, productID uuid.UUID, quantity int) int {
+	returned := restocked[productID]
+	if returned == 0 {
+		return quantity
+	}
+	if returned > quantity {
+		returned = quantity
 	}
+	restocked[productID] -= returned
+	return quantity - returned
 }
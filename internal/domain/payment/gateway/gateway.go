@@ -0,0 +1,94 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+)
+
+type IntentStatus string
+
+const (
+	IntentRequiresCapture   IntentStatus = "requires_capture"
+	IntentSucceeded         IntentStatus = "succeeded"
+	IntentDeclined          IntentStatus = "declined"
+	IntentPending           IntentStatus = "pending"
+	IntentRefunded          IntentStatus = "refunded"
+	IntentPartiallyRefunded IntentStatus = "partially_refunded"
+)
+
+// Intent is a provider-side attempt to collect an amount for one payment.
+type Intent struct {
+	ID             string       `json:"id"`
+	Reference      string       `json:"reference"`
+	Amount         money.Amount `json:"amount"`
+	RefundedAmount money.Amount `json:"refundedAmount"`
+	Status         IntentStatus `json:"status"`
+	DeclineReason  string       `json:"declineReason,omitempty"`
+}
+
+type CreateIntentRequest struct {
+	// Reference is our payment ID, echoed back by the provider.
+	Reference string
+	Amount    money.Amount
+	Currency  money.Currency
+	// Token is the provider token of the customer's payment instrument.
+	Token string
+}
+
+type Refund struct {
+	ID       string       `json:"id"`
+	IntentID string       `json:"intentId"`
+	Amount   money.Amount `json:"amount"`
+}
+
+// PaymentGateway is a payment service provider behind one payment method.
+type PaymentGateway interface {
+	Method() string
+	CreateIntent(ctx context.Context, req CreateIntentRequest) (res Intent, err error)
+	Capture(ctx context.Context, intentID string) (res Intent, err error)
+	Refund(ctx context.Context, intentID string, amount money.Amount) (res Refund, err error)
+	QueryStatus(ctx context.Context, intentID string) (res Intent, err error)
+}
+
+// Registry resolves the gateway for a payment_method.
+type Registry struct {
+	gateways map[string]PaymentGateway
+}
+
+// ProvideRegistry is the provider for Registry. Only the in-process mock
+// provider is shipped; real providers register themselves here.
+func ProvideRegistry() *Registry {
+	registry := &Registry{
+		gateways: make(map[string]PaymentGateway),
+	}
+	registry.Register(NewMockGateway())
+	return registry
+}
+
+func (r *Registry) Register(gw PaymentGateway) {
+	r.gateways[strings.ToLower(gw.Method())] = gw
+}
+
+// Methods returns the payment methods of every registered gateway.
+func (r *Registry) Methods() []string {
+	methods := make([]string, 0, len(r.gateways))
+	for method := range r.gateways {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// Get returns the gateway of method.
+func (r *Registry) Get(method string) (gw PaymentGateway, err error) {
+	gw, found := r.gateways[strings.ToLower(method)]
+	if !found {
+		return nil, failure.BadRequestFromString(fmt.Sprintf("unsupported payment method: %s", method))
+	}
+	return gw, nil
+}
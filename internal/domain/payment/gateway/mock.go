@@ -0,0 +1,125 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+)
+
+const (
+	MockMethod = "mock"
+
+	// MockTokenDecline makes the mock provider decline the charge.
+	MockTokenDecline = "tok_decline"
+	// MockTokenPending leaves the charge pending until it is settled
+	// through a webhook.
+	MockTokenPending = "tok_pending"
+)
+
+// MockGateway is an in-process sandbox provider. Charges succeed unless the
+// intent was created with one of the MockToken values.
+type MockGateway struct {
+	mu      sync.Mutex
+	intents map[string]*Intent
+	tokens  map[string]string
+}
+
+func NewMockGateway() *MockGateway {
+	return &MockGateway{
+		intents: make(map[string]*Intent),
+		tokens:  make(map[string]string),
+	}
+}
+
+func (g *MockGateway) Method() string {
+	return MockMethod
+}
+
+func (g *MockGateway) CreateIntent(ctx context.Context, req CreateIntentRequest) (res Intent, err error) {
+	if req.Amount <= 0 {
+		return res, failure.BadRequestFromString("amount must be greater than zero")
+	}
+	id, err := uuid.NewV4()
+	if err != nil {
+		return
+	}
+	intent := &Intent{
+		ID:        fmt.Sprintf("mock_pi_%s", id),
+		Reference: req.Reference,
+		Amount:    req.Amount,
+		Status:    IntentRequiresCapture,
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.intents[intent.ID] = intent
+	g.tokens[intent.ID] = req.Token
+	return *intent, nil
+}
+
+func (g *MockGateway) Capture(ctx context.Context, intentID string) (res Intent, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	intent, found := g.intents[intentID]
+	if !found {
+		return res, failure.NotFound("payment intent")
+	}
+	if intent.Status != IntentRequiresCapture {
+		return *intent, nil
+	}
+
+	switch g.tokens[intentID] {
+	case MockTokenDecline:
+		intent.Status = IntentDeclined
+		intent.DeclineReason = "card_declined"
+	case MockTokenPending:
+		intent.Status = IntentPending
+	default:
+		intent.Status = IntentSucceeded
+	}
+	return *intent, nil
+}
+
+func (g *MockGateway) Refund(ctx context.Context, intentID string, amount money.Amount) (res Refund, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	intent, found := g.intents[intentID]
+	if !found {
+		return res, failure.NotFound("payment intent")
+	}
+	if intent.Status != IntentSucceeded && intent.Status != IntentPartiallyRefunded {
+		return res, failure.Conflict("refund", "payment intent", fmt.Sprintf("intent is %s", intent.Status))
+	}
+	if amount <= 0 || intent.RefundedAmount+amount > intent.Amount {
+		return res, failure.UnprocessableEntity("refund amount exceeds the captured amount")
+	}
+
+	intent.RefundedAmount += amount
+	intent.Status = IntentPartiallyRefunded
+	if intent.RefundedAmount >= intent.Amount {
+		intent.Status = IntentRefunded
+	}
+	id, err := uuid.NewV4()
+	if err != nil {
+		return
+	}
+	return Refund{
+		ID:       fmt.Sprintf("mock_re_%s", id),
+		IntentID: intentID,
+		Amount:   amount,
+	}, nil
+}
+
+func (g *MockGateway) QueryStatus(ctx context.Context, intentID string) (res Intent, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	intent, found := g.intents[intentID]
+	if !found {
+		return res, failure.NotFound("payment intent")
+	}
+	return *intent, nil
+}
@@ -4,35 +4,59 @@ import (
 	"time"
 
 	"github.com/azka-zaydan/synapsis-test/internal/domain/payment/model"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
 	"github.com/gofrs/uuid"
 	"github.com/guregu/null"
 )
 
 type PaymentResponse struct {
-	ID            string      `json:"id"`
-	UserID        string      `json:"userId"`
-	PaymentMethod string      `json:"paymentMethod"`
-	OrderID       string      `json:"orderId"`
-	TotalPrice    float64     `json:"totalPrice"`
-	Status        int         `json:"status"`
-	PaymentAt     null.Time   `json:"paymentAt"`
-	CreatedBy     string      `json:"createdBy"`
-	MetaCreatedAt time.Time   `json:"metaCreatedAt"`
-	UpdatedBy     string      `json:"updatedBy"`
-	MetaUpdatedAt time.Time   `json:"metaUpdatedAt"`
-	DeletedBy     null.String `json:"deletedBy"`
-	MetaDeletedAt null.Time   `json:"metaDeletedAt"`
+	ID                string            `json:"id"`
+	UserID            string            `json:"userId"`
+	PaymentMethod     string            `json:"paymentMethod"`
+	ProviderReference null.String       `json:"providerReference"`
+	OrderID           string            `json:"orderId"`
+	TotalPrice        money.Amount      `json:"totalPrice"`
+	Currency          money.Currency    `json:"currency"`
+	Conversions       money.Conversions `json:"conversions"`
+	Status            int               `json:"status"`
+	PaymentAt         null.Time         `json:"paymentAt"`
+	CreatedBy         string            `json:"createdBy"`
+	MetaCreatedAt     time.Time         `json:"metaCreatedAt"`
+	UpdatedBy         string            `json:"updatedBy"`
+	MetaUpdatedAt     time.Time         `json:"metaUpdatedAt"`
+	DeletedBy         null.String       `json:"deletedBy"`
+	MetaDeletedAt     null.Time         `json:"metaDeletedAt"`
 }
 
 type PayRequest struct {
-	OrderID string `json:"orderId"`
+	OrderID       string `json:"orderId"`
+	PaymentMethod string `json:"paymentMethod"`
+	// PaymentToken identifies the customer's payment instrument at the provider.
+	PaymentToken string `json:"paymentToken"`
+	// Amount is the total the customer agreed to pay, it must match the order.
+	Amount money.Amount `json:"amount"`
+}
+
+const (
+	WebhookEventPaymentSucceeded = "payment.succeeded"
+	WebhookEventPaymentFailed    = "payment.failed"
+)
+
+// WebhookEvent is the payload payment providers post to the webhook.
+type WebhookEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		IntentID string       `json:"intentId"`
+		Amount   money.Amount `json:"amount"`
+	} `json:"data"`
 }
 
 type CreatePaymentRequest struct {
-	UserID        string  `json:"user_id"`
-	PaymentMethod string  `json:"payment_method"`
-	OrderID       string  `json:"order_id"`
-	TotalPrice    float64 `json:"total_price"`
+	UserID        string       `json:"user_id"`
+	PaymentMethod string       `json:"payment_method"`
+	OrderID       string       `json:"order_id"`
+	TotalPrice    money.Amount `json:"total_price"`
 }
 
 func (d *CreatePaymentRequest) ToModel() (res model.Payment, err error) {
@@ -62,16 +86,19 @@ func (d *CreatePaymentRequest) ToModel() (res model.Payment, err error) {
 
 func NewPaymentResponse(payment model.Payment) PaymentResponse {
 	return PaymentResponse{
-		ID:            payment.ID.String(),
-		UserID:        payment.UserID.String(),
-		PaymentMethod: payment.PaymentMethod,
-		OrderID:       payment.OrderID.String(),
-		TotalPrice:    payment.TotalPrice,
-		Status:        payment.Status,
-		PaymentAt:     payment.PaymentAt,
-		CreatedBy:     payment.CreatedBy.String(),
-		MetaCreatedAt: payment.MetaCreatedAt,
-		UpdatedBy:     payment.UpdatedBy.String(),
-		MetaUpdatedAt: payment.MetaUpdatedAt,
+		ID:                payment.ID.String(),
+		UserID:            payment.UserID.String(),
+		PaymentMethod:     payment.PaymentMethod,
+		ProviderReference: payment.ProviderReference,
+		OrderID:           payment.OrderID.String(),
+		TotalPrice:        payment.TotalPrice,
+		Currency:          payment.Currency,
+		Conversions:       payment.Conversions,
+		Status:            payment.Status,
+		PaymentAt:         payment.PaymentAt,
+		CreatedBy:         payment.CreatedBy.String(),
+		MetaCreatedAt:     payment.MetaCreatedAt,
+		UpdatedBy:         payment.UpdatedBy.String(),
+		MetaUpdatedAt:     payment.MetaUpdatedAt,
 	}
 }
@@ -0,0 +1,111 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/payment/model"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+type RefundItemRequest struct {
+	OrderDetailID string `json:"orderDetailId"`
+	Quantity      int    `json:"quantity"`
+}
+
+// RefundRequest refunds Amount of a payment. Items optionally name the
+// purchased lines being returned; with Restock they are put back in stock.
+type RefundRequest struct {
+	Amount  money.Amount        `json:"amount"`
+	Reason  string              `json:"reason"`
+	Restock bool                `json:"restock"`
+	Items   []RefundItemRequest `json:"items"`
+}
+
+type RefundItemResponse struct {
+	ID            string `json:"id"`
+	OrderDetailID string `json:"orderDetailId"`
+	ProductID     string `json:"productId"`
+	Quantity      int    `json:"quantity"`
+}
+
+type RefundResponse struct {
+	ID                string               `json:"id"`
+	PaymentID         string               `json:"paymentId"`
+	OrderID           string               `json:"orderId"`
+	Amount            money.Amount         `json:"amount"`
+	Reason            string               `json:"reason"`
+	Status            int                  `json:"status"`
+	StatusName        string               `json:"statusName"`
+	ProviderReference null.String          `json:"providerReference"`
+	Restock           bool                 `json:"restock"`
+	Items             []RefundItemResponse `json:"items"`
+	CreatedBy         string               `json:"createdBy"`
+	MetaCreatedAt     time.Time            `json:"metaCreatedAt"`
+}
+
+type RefundResultResponse struct {
+	Refund  RefundResponse  `json:"refund"`
+	Payment PaymentResponse `json:"payment"`
+}
+
+type RefundListResponse struct {
+	Refunds          []RefundResponse `json:"refunds"`
+	TotalRefunded    money.Amount     `json:"totalRefunded"`
+	RefundableAmount money.Amount     `json:"refundableAmount"`
+}
+
+func (d *RefundRequest) ToModel(payment model.Payment, by uuid.UUID) model.Refund {
+	id, _ := uuid.NewV4()
+	return model.Refund{
+		ID:        id,
+		PaymentID: payment.ID,
+		OrderID:   payment.OrderID,
+		Amount:    d.Amount,
+		Reason:    d.Reason,
+		Status:    int(model.RefundRequested),
+		Restock:   d.Restock,
+		CreatedBy: by,
+		UpdatedBy: by,
+	}
+}
+
+func NewRefundItem(refundID, orderDetailID, productID uuid.UUID, quantity int, by uuid.UUID) model.RefundItem {
+	id, _ := uuid.NewV4()
+	return model.RefundItem{
+		ID:            id,
+		RefundID:      refundID,
+		OrderDetailID: orderDetailID,
+		ProductID:     productID,
+		Quantity:      quantity,
+		CreatedBy:     by,
+		UpdatedBy:     by,
+	}
+}
+
+func NewRefundResponse(refund model.Refund) RefundResponse {
+	items := make([]RefundItemResponse, 0, len(refund.Items))
+	for _, item := range refund.Items {
+		items = append(items, RefundItemResponse{
+			ID:            item.ID.String(),
+			OrderDetailID: item.OrderDetailID.String(),
+			ProductID:     item.ProductID.String(),
+			Quantity:      item.Quantity,
+		})
+	}
+	return RefundResponse{
+		ID:                refund.ID.String(),
+		PaymentID:         refund.PaymentID.String(),
+		OrderID:           refund.OrderID.String(),
+		Amount:            refund.Amount,
+		Reason:            refund.Reason,
+		Status:            refund.Status,
+		StatusName:        model.RefundStatus(refund.Status).String(),
+		ProviderReference: refund.ProviderReference,
+		Restock:           refund.Restock,
+		Items:             items,
+		CreatedBy:         refund.CreatedBy.String(),
+		MetaCreatedAt:     refund.MetaCreatedAt,
+	}
+}
@@ -1,8 +1,10 @@
 package model
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/azka-zaydan/synapsis-test/shared/money"
 	"github.com/gofrs/uuid"
 	"github.com/guregu/null"
 )
@@ -10,27 +12,105 @@ import (
 type PaymentStatus int
 
 var (
-	Paid   PaymentStatus = 1
-	Unpaid PaymentStatus = 0
+	Paid              PaymentStatus = 1
+	Unpaid            PaymentStatus = 0
+	Voided            PaymentStatus = 2
+	Cancelled         PaymentStatus = 3
+	RefundPending     PaymentStatus = 4
+	Pending           PaymentStatus = 5
+	PartiallyRefunded PaymentStatus = 6
+	Refunded          PaymentStatus = 7
 )
 
+func (s PaymentStatus) String() string {
+	switch s {
+	case Unpaid:
+		return "unpaid"
+	case Paid:
+		return "paid"
+	case Voided:
+		return "voided"
+	case Cancelled:
+		return "cancelled"
+	case RefundPending:
+		return "refund pending"
+	case Pending:
+		return "pending"
+	case PartiallyRefunded:
+		return "partially refunded"
+	case Refunded:
+		return "refunded"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
 type Payment struct {
-	ID            uuid.UUID     `db:"id"`
-	UserID        uuid.UUID     `db:"user_id"`
-	OrderID       uuid.UUID     `db:"order_id"`
-	PaymentMethod string        `db:"payment_method"`
-	TotalPrice    float64       `db:"total_price"`
-	Status        int           `db:"status"`
-	PaymentAt     null.Time     `db:"payment_at"`
-	CreatedBy     uuid.UUID     `db:"created_by"`
-	MetaCreatedAt time.Time     `db:"meta_created_at"`
-	UpdatedBy     uuid.UUID     `db:"updated_by"`
-	MetaUpdatedAt time.Time     `db:"meta_updated_at"`
-	DeletedBy     null.Time     `db:"deleted_by"`
-	MetaDeletedAt uuid.NullUUID `db:"meta_deleted_at"`
+	ID                uuid.UUID      `db:"id"`
+	UserID            uuid.UUID      `db:"user_id"`
+	OrderID           uuid.UUID      `db:"order_id"`
+	PaymentMethod     string         `db:"payment_method"`
+	ProviderReference null.String    `db:"provider_reference"`
+	TotalPrice        money.Amount   `db:"total_price"`
+	Currency          money.Currency `db:"currency"`
+	// Conversions are the exchange rates the total was priced with.
+	Conversions   money.Conversions `db:"conversion_snapshot"`
+	Status        int               `db:"status"`
+	PaymentAt     null.Time         `db:"payment_at"`
+	CreatedBy     uuid.UUID         `db:"created_by"`
+	MetaCreatedAt time.Time         `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID         `db:"updated_by"`
+	MetaUpdatedAt time.Time         `db:"meta_updated_at"`
+	DeletedBy     null.Time         `db:"deleted_by"`
+	MetaDeletedAt uuid.NullUUID     `db:"meta_deleted_at"`
 }
 
 func (m *Payment) Pay() {
 	m.PaymentAt = null.TimeFrom(time.Now())
 	m.Status = int(Paid)
 }
+
+// MarkPending records a charge the provider accepted but has not settled.
+func (m *Payment) MarkPending() {
+	m.Status = int(Pending)
+}
+
+// Void marks an unpaid payment as no longer collectable.
+func (m *Payment) Void() {
+	m.Status = int(Voided)
+}
+
+// Cancel marks an unpaid payment of a cancelled order.
+func (m *Payment) Cancel() {
+	m.Status = int(Cancelled)
+}
+
+// IsRefundable reports whether money was collected for the payment and may
+// still be returned.
+func (m *Payment) IsRefundable() bool {
+	switch PaymentStatus(m.Status) {
+	case Paid, PartiallyRefunded, RefundPending:
+		return true
+	default:
+		return false
+	}
+}
+
+// ApplyRefunds sets the status from the amount refunded so far.
+func (m *Payment) ApplyRefunds(refunded money.Amount) {
+	if refunded >= m.TotalPrice {
+		m.Status = int(Refunded)
+		return
+	}
+	if m.Status == int(RefundPending) {
+		// the order was cancelled, the rest is still owed
+		return
+	}
+	m.Status = int(PartiallyRefunded)
+}
+
+// MarkRefundPending marks a paid payment whose order was cancelled and is
+// waiting for the money to be returned.
+func (m *Payment) MarkRefundPending() {
+	m.Status = int(RefundPending)
+}
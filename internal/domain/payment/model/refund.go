@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+type RefundStatus int
+
+var (
+	RefundRequested RefundStatus = 0
+	RefundSucceeded RefundStatus = 1
+	RefundFailed    RefundStatus = 2
+)
+
+func (s RefundStatus) String() string {
+	switch s {
+	case RefundRequested:
+		return "requested"
+	case RefundSucceeded:
+		return "succeeded"
+	case RefundFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// Refund is an entry of the refund ledger of a payment. A refund is
+// recorded as requested before the provider is called, so concurrent
+// refunds of the same payment can never exceed what was collected.
+type Refund struct {
+	ID                uuid.UUID     `db:"id"`
+	PaymentID         uuid.UUID     `db:"payment_id"`
+	OrderID           uuid.UUID     `db:"order_id"`
+	Amount            money.Amount  `db:"amount"`
+	Reason            string        `db:"reason"`
+	Status            int           `db:"status"`
+	ProviderReference null.String   `db:"provider_reference"`
+	Restock           bool          `db:"restock"`
+	CreatedBy         uuid.UUID     `db:"created_by"`
+	MetaCreatedAt     time.Time     `db:"meta_created_at"`
+	UpdatedBy         uuid.UUID     `db:"updated_by"`
+	MetaUpdatedAt     time.Time     `db:"meta_updated_at"`
+	DeletedBy         uuid.NullUUID `db:"deleted_by"`
+	MetaDeletedAt     null.Time     `db:"meta_deleted_at"`
+	Items             []RefundItem  `db:"-"`
+}
+
+// RefundItem is a purchased line returned with a refund.
+type RefundItem struct {
+	ID            uuid.UUID     `db:"id"`
+	RefundID      uuid.UUID     `db:"refund_id"`
+	OrderDetailID uuid.UUID     `db:"order_detail_id"`
+	ProductID     uuid.UUID     `db:"product_id"`
+	Quantity      int           `db:"quantity"`
+	CreatedBy     uuid.UUID     `db:"created_by"`
+	MetaCreatedAt time.Time     `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID     `db:"updated_by"`
+	MetaUpdatedAt time.Time     `db:"meta_updated_at"`
+	DeletedBy     uuid.NullUUID `db:"deleted_by"`
+	MetaDeletedAt null.Time     `db:"meta_deleted_at"`
+}
+
+func (m *Refund) Succeed(providerReference string) {
+	m.Status = int(RefundSucceeded)
+	m.ProviderReference = null.StringFrom(providerReference)
+}
+
+func (m *Refund) Fail() {
+	m.Status = int(RefundFailed)
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/payment/model"
+	"github.com/azka-zaydan/synapsis-test/shared/logger"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/jmoiron/sqlx"
+)
+
+type RefundRepo interface {
+	CreateRefundTx(ctx context.Context, tx *sqlx.Tx, refund *model.Refund) (err error)
+	CreateRefundItemTx(ctx context.Context, tx *sqlx.Tx, item *model.RefundItem) (err error)
+	UpdateRefundTx(ctx context.Context, tx *sqlx.Tx, refund *model.Refund) (err error)
+	GetRefundsByPaymentID(ctx context.Context, paymentId string) (res []model.Refund, err error)
+	GetRefundItemsByPaymentID(ctx context.Context, paymentId string) (res []model.RefundItem, err error)
+	GetRefundItemsByPaymentIDTx(ctx context.Context, tx *sqlx.Tx, paymentId string) (res []model.RefundItem, err error)
+	GetRestockedRefundItemsByPaymentIDTx(ctx context.Context, tx *sqlx.Tx, paymentId string) (res []model.RefundItem, err error)
+	GetRefundTotalsByPaymentIDTx(ctx context.Context, tx *sqlx.Tx, paymentId string) (requested money.Amount, succeeded money.Amount, err error)
+}
+
+func (repo *PaymentRepositoryMySQL) CreateRefundTx(ctx context.Context, tx *sqlx.Tx, refund *model.Refund) (err error) {
+	_, err = tx.NamedExecContext(ctx, refundInsertQuery, refund)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PaymentRepositoryMySQL) CreateRefundItemTx(ctx context.Context, tx *sqlx.Tx, item *model.RefundItem) (err error) {
+	_, err = tx.NamedExecContext(ctx, refundItemInsertQuery, item)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PaymentRepositoryMySQL) UpdateRefundTx(ctx context.Context, tx *sqlx.Tx, refund *model.Refund) (err error) {
+	_, err = tx.NamedExecContext(ctx, refundUpdateQuery, refund)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PaymentRepositoryMySQL) GetRefundsByPaymentID(ctx context.Context, paymentId string) (res []model.Refund, err error) {
+	err = repo.DB.Read.SelectContext(ctx, &res, fmt.Sprintf("%s WHERE payment_id = ? ORDER BY meta_created_at, id", refundSelectQuery), paymentId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PaymentRepositoryMySQL) GetRefundItemsByPaymentID(ctx context.Context, paymentId string) (res []model.RefundItem, err error) {
+	err = repo.DB.Read.SelectContext(ctx, &res, fmt.Sprintf("%s WHERE r.payment_id = ?", refundItemSelectQuery), paymentId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PaymentRepositoryMySQL) GetRefundItemsByPaymentIDTx(ctx context.Context, tx *sqlx.Tx, paymentId string) (res []model.RefundItem, err error) {
+	err = tx.SelectContext(ctx, &res, fmt.Sprintf("%s WHERE r.payment_id = ? AND r.status <> ?", refundItemSelectQuery), paymentId, model.RefundFailed)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PaymentRepositoryMySQL) GetRestockedRefundItemsByPaymentIDTx(ctx context.Context, tx *sqlx.Tx, paymentId string) (res []model.RefundItem, err error) {
+	err = tx.SelectContext(ctx, &res, fmt.Sprintf("%s WHERE r.payment_id = ? AND r.status = ? AND r.restock = TRUE", refundItemSelectQuery), paymentId, model.RefundSucceeded)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PaymentRepositoryMySQL) GetRefundTotalsByPaymentIDTx(ctx context.Context, tx *sqlx.Tx, paymentId string) (requested money.Amount, succeeded money.Amount, err error) {
+	var totals struct {
+		Requested money.Amount `db:"requested"`
+		Succeeded money.Amount `db:"succeeded"`
+	}
+	err = tx.GetContext(ctx, &totals, refundTotalsQuery, model.RefundRequested, model.RefundSucceeded, paymentId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return totals.Requested, totals.Succeeded, nil
+}
+
+var (
+	refundInsertQuery = `
+	INSERT INTO refund (
+		id,
+		payment_id,
+		order_id,
+		amount,
+		reason,
+		status,
+		provider_reference,
+		restock,
+		created_by,
+		updated_by
+	) VALUES (
+		:id,
+		:payment_id,
+		:order_id,
+		:amount,
+		:reason,
+		:status,
+		:provider_reference,
+		:restock,
+		:created_by,
+		:updated_by
+	)`
+	refundSelectQuery = `
+	SELECT
+		id,
+		payment_id,
+		order_id,
+		amount,
+		reason,
+		status,
+		provider_reference,
+		restock,
+		created_by,
+		meta_created_at,
+		updated_by,
+		meta_updated_at
+	FROM refund`
+	refundUpdateQuery = `
+	UPDATE refund SET
+		status = :status,
+		provider_reference = :provider_reference,
+		updated_by = :updated_by
+	WHERE id = :id`
+	refundTotalsQuery = `
+	SELECT
+		COALESCE(SUM(CASE WHEN status = ? THEN amount ELSE 0 END), 0) AS requested,
+		COALESCE(SUM(CASE WHEN status = ? THEN amount ELSE 0 END), 0) AS succeeded
+	FROM refund
+	WHERE payment_id = ?`
+	refundItemInsertQuery = `
+	INSERT INTO refund_item (
+		id,
+		refund_id,
+		order_detail_id,
+		product_id,
+		quantity,
+		created_by,
+		updated_by
+	) VALUES (
+		:id,
+		:refund_id,
+		:order_detail_id,
+		:product_id,
+		:quantity,
+		:created_by,
+		:updated_by
+	)`
+	refundItemSelectQuery = `
+	SELECT
+		ri.id,
+		ri.refund_id,
+		ri.order_detail_id,
+		ri.product_id,
+		ri.quantity,
+		ri.created_by,
+		ri.meta_created_at,
+		ri.updated_by,
+		ri.meta_updated_at
+	FROM refund_item ri
+	JOIN refund r ON r.id = ri.refund_id`
+)
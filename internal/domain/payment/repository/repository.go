@@ -7,12 +7,21 @@ import (
 	"github.com/azka-zaydan/synapsis-test/infras"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/payment/model"
 	"github.com/azka-zaydan/synapsis-test/shared/logger"
+	"github.com/jmoiron/sqlx"
 )
 
 type PaymentRepository interface {
+	RefundRepo
 	CreatePayment(ctx context.Context, payment *model.Payment) (err error)
 	GetPaymentByOrderID(ctx context.Context, orderId string) (res model.Payment, err error)
 	UpdatePayment(ctx context.Context, payment *model.Payment) (err error)
+	CreatePaymentTx(ctx context.Context, tx *sqlx.Tx, payment *model.Payment) (err error)
+	UpdatePaymentTx(ctx context.Context, tx *sqlx.Tx, payment *model.Payment) (err error)
+	GetPaymentByOrderIDForUpdate(ctx context.Context, tx *sqlx.Tx, orderId string) (res model.Payment, err error)
+	GetPaymentByProviderReference(ctx context.Context, reference string) (res model.Payment, err error)
+	UpdatePaymentStatusFrom(ctx context.Context, paymentId string, from model.PaymentStatus, to model.PaymentStatus) (updated bool, err error)
+	GetPaymentByID(ctx context.Context, paymentId string) (res model.Payment, err error)
+	GetPaymentByIDForUpdate(ctx context.Context, tx *sqlx.Tx, paymentId string) (res model.Payment, err error)
 }
 
 type PaymentRepositoryMySQL struct {
@@ -52,14 +61,85 @@ func (repo *PaymentRepositoryMySQL) UpdatePayment(ctx context.Context, payment *
 	return
 }
 
+func (repo *PaymentRepositoryMySQL) CreatePaymentTx(ctx context.Context, tx *sqlx.Tx, payment *model.Payment) (err error) {
+	_, err = tx.NamedExecContext(ctx, paymentInsertQuery, payment)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PaymentRepositoryMySQL) UpdatePaymentTx(ctx context.Context, tx *sqlx.Tx, payment *model.Payment) (err error) {
+	_, err = tx.NamedExecContext(ctx, paymentUpdateQuery, payment)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PaymentRepositoryMySQL) GetPaymentByOrderIDForUpdate(ctx context.Context, tx *sqlx.Tx, orderId string) (res model.Payment, err error) {
+	err = tx.GetContext(ctx, &res, fmt.Sprintf("%s WHERE order_id = ? FOR UPDATE", paymentSelectQuery), orderId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PaymentRepositoryMySQL) GetPaymentByProviderReference(ctx context.Context, reference string) (res model.Payment, err error) {
+	err = repo.DB.Read.GetContext(ctx, &res, fmt.Sprintf("%s WHERE provider_reference = ?", paymentSelectQuery), reference)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PaymentRepositoryMySQL) UpdatePaymentStatusFrom(ctx context.Context, paymentId string, from model.PaymentStatus, to model.PaymentStatus) (updated bool, err error) {
+	result, err := repo.DB.Write.ExecContext(ctx, paymentStatusUpdateQuery, to, paymentId, from)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return affected > 0, nil
+}
+
+func (repo *PaymentRepositoryMySQL) GetPaymentByID(ctx context.Context, paymentId string) (res model.Payment, err error) {
+	err = repo.DB.Read.GetContext(ctx, &res, fmt.Sprintf("%s WHERE id = ?", paymentSelectQuery), paymentId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PaymentRepositoryMySQL) GetPaymentByIDForUpdate(ctx context.Context, tx *sqlx.Tx, paymentId string) (res model.Payment, err error) {
+	err = tx.GetContext(ctx, &res, fmt.Sprintf("%s WHERE id = ? FOR UPDATE", paymentSelectQuery), paymentId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
 var (
 	paymentInsertQuery = `
 	INSERT INTO payment (
 		id,
 		user_id,
 		payment_method,
+		provider_reference,
 		order_id,
 		total_price,
+		currency,
+		conversion_snapshot,
 		status,
 		payment_at,
 		created_by,
@@ -68,8 +148,11 @@ var (
 		:id,
 		:user_id,
 		:payment_method,
+		:provider_reference,
 		:order_id,
 		:total_price,
+		:currency,
+		:conversion_snapshot,
 		:status,
 		:payment_at,
 		:created_by,
@@ -80,17 +163,25 @@ var (
 		id,
 		user_id,
 		payment_method,
+		provider_reference,
 		order_id,
 		total_price,
+		currency,
+		conversion_snapshot,
 		status,
 		payment_at,
 		created_by,
 		updated_by
 	FROM payment`
+	paymentStatusUpdateQuery = `
+	UPDATE payment SET
+		status = ?
+	WHERE id = ? AND status = ?`
 	paymentUpdateQuery = `
 	UPDATE payment SET
 		user_id = :user_id,
 		payment_method = :payment_method,
+		provider_reference = :provider_reference,
 		order_id = :order_id,
 		total_price = :total_price,
 		status = :status,
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	orderModel "github.com/azka-zaydan/synapsis-test/internal/domain/order/model"
+	paymentModel "github.com/azka-zaydan/synapsis-test/internal/domain/payment/model"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/payment/model/dto"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// Refund returns part or all of a collected payment through its provider.
+// The refund is put in the ledger before the provider is called and is
+// settled or failed afterwards, so the ledger never allows refunding more
+// than TotalPrice even under concurrent requests.
+func (s *PaymentServiceImpl) Refund(ctx context.Context, paymentID string, req dto.RefundRequest, adminID uuid.UUID) (res dto.RefundResultResponse, err error) {
+	if !req.Amount.IsPositive() {
+		err = failure.UnprocessableEntity("amount must be greater than zero")
+		log.Error().Err(err).Msg("[Refund] Invalid Amount")
+		return
+	}
+
+	payment, refund, err := s.requestRefund(ctx, paymentID, req, adminID)
+	if err != nil {
+		log.Error().Err(err).Msg("[Refund] Failed requestRefund")
+		return
+	}
+
+	providerReference, err := s.providerRefund(ctx, payment, refund)
+	if err != nil {
+		log.Error().Err(err).Msg("[Refund] Failed providerRefund")
+		s.failRefund(ctx, &refund, adminID)
+		return
+	}
+
+	payment, err = s.settleRefund(ctx, &refund, providerReference, adminID)
+	if err != nil {
+		// the money is back with the customer; the entry stays requested so
+		// it keeps counting against the payment until it is reconciled
+		log.Error().Err(err).Str("refund", refund.ID.String()).Msg("[Refund] Failed settleRefund")
+		return
+	}
+
+	return dto.RefundResultResponse{
+		Refund:  dto.NewRefundResponse(refund),
+		Payment: dto.NewPaymentResponse(payment),
+	}, nil
+}
+
+func (s *PaymentServiceImpl) providerRefund(ctx context.Context, payment paymentModel.Payment, refund paymentModel.Refund) (providerReference string, err error) {
+	gw, err := s.Gateways.Get(payment.PaymentMethod)
+	if err != nil {
+		log.Error().Err(err).Msg("[providerRefund] Failed Getting Gateway")
+		return
+	}
+	res, err := gw.Refund(ctx, payment.ProviderReference.String, refund.Amount)
+	if err != nil {
+		log.Error().Err(err).Msg("[providerRefund] Failed Refund")
+		return
+	}
+	return res.ID, nil
+}
+
+// requestRefund validates a refund against the ledger and records it as
+// requested. Like every path changing an order and its payment, it locks
+// the order before the payment.
+func (s *PaymentServiceImpl) requestRefund(ctx context.Context, paymentID string, req dto.RefundRequest, adminID uuid.UUID) (payment paymentModel.Payment, refund paymentModel.Refund, err error) {
+	err = s.DB.WithTransaction(func(tx *sqlx.Tx, e chan error) {
+		var err error
+		payment, err = s.Repo.GetPaymentByID(ctx, paymentID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				err = failure.NotFound("payment")
+			}
+			e <- err
+			return
+		}
+		order, err := s.OrderRepo.GetOrderByIDForUpdate(ctx, tx, payment.OrderID.String())
+		if err != nil {
+			e <- err
+			return
+		}
+		payment, err = s.Repo.GetPaymentByIDForUpdate(ctx, tx, paymentID)
+		if err != nil {
+			e <- err
+			return
+		}
+		if !payment.IsRefundable() {
+			e <- failure.Conflict("refund", "payment", fmt.Sprintf("payment is %s", paymentModel.PaymentStatus(payment.Status)))
+			return
+		}
+		if !payment.ProviderReference.Valid {
+			e <- failure.Conflict("refund", "payment", "payment has no provider charge")
+			return
+		}
+
+		requested, succeeded, err := s.Repo.GetRefundTotalsByPaymentIDTx(ctx, tx, paymentID)
+		if err != nil {
+			e <- err
+			return
+		}
+		remaining := payment.TotalPrice - requested - succeeded
+		if req.Amount > remaining {
+			e <- failure.UnprocessableEntity(fmt.Sprintf("amount exceeds the refundable amount of %s", remaining))
+			return
+		}
+
+		refund = req.ToModel(payment, adminID)
+		refund.Items, err = s.refundItems(ctx, tx, payment, order, req, refund.ID, adminID)
+		if err != nil {
+			e <- err
+			return
+		}
+
+		err = s.Repo.CreateRefundTx(ctx, tx, &refund)
+		if err != nil {
+			e <- err
+			return
+		}
+		for i := range refund.Items {
+			err = s.Repo.CreateRefundItemTx(ctx, tx, &refund.Items[i])
+			if err != nil {
+				e <- err
+				return
+			}
+		}
+		e <- nil
+	})
+	return
+}
+
+// refundItems resolves the returned lines of a refund request on the
+// locked order. A line can not be returned more times than it was bought.
+func (s *PaymentServiceImpl) refundItems(ctx context.Context, tx *sqlx.Tx, payment paymentModel.Payment, order orderModel.Order, req dto.RefundRequest, refundID uuid.UUID, adminID uuid.UUID) (items []paymentModel.RefundItem, err error) {
+	if len(req.Items) == 0 {
+		if req.Restock {
+			return nil, failure.UnprocessableEntity("items are required to restock")
+		}
+		return
+	}
+
+	if req.Restock && order.Status == int(orderModel.OrderCancelledStatus) {
+		// cancelling already returned the stock of every line
+		return nil, failure.Conflict("restock", "order", "stock was returned when the order was cancelled")
+	}
+
+	details, err := s.OrderRepo.GetOrderDetailsByOrderID(ctx, payment.OrderID.String())
+	if err != nil {
+		return
+	}
+	refunded, err := s.Repo.GetRefundItemsByPaymentIDTx(ctx, tx, payment.ID.String())
+	if err != nil {
+		return
+	}
+	remaining := make(map[uuid.UUID]int)
+	for _, detail := range details {
+		remaining[detail.ID] = detail.TotalItems
+	}
+	for _, item := range refunded {
+		remaining[item.OrderDetailID] -= item.Quantity
+	}
+
+	for _, reqItem := range req.Items {
+		detailID, parseErr := uuid.FromString(reqItem.OrderDetailID)
+		if parseErr != nil {
+			return nil, failure.BadRequest(parseErr)
+		}
+		left, found := remaining[detailID]
+		if !found {
+			return nil, failure.UnprocessableEntity(fmt.Sprintf("order detail %s is not part of the order", reqItem.OrderDetailID))
+		}
+		if reqItem.Quantity <= 0 || reqItem.Quantity > left {
+			return nil, failure.UnprocessableEntity(fmt.Sprintf("quantity of order detail %s must be between 1 and %d", reqItem.OrderDetailID, left))
+		}
+		remaining[detailID] -= reqItem.Quantity
+
+		for _, detail := range details {
+			if detail.ID == detailID {
+				items = append(items, dto.NewRefundItem(refundID, detailID, detail.ProductID, reqItem.Quantity, adminID))
+				break
+			}
+		}
+	}
+	return
+}
+
+// settleRefund records a refund the provider accepted, moves the payment to
+// partially refunded or refunded and restocks the returned lines on request.
+// Lines of an order cancelled or expired since the refund was requested are
+// not restocked, that already returned all of its stock.
+func (s *PaymentServiceImpl) settleRefund(ctx context.Context, refund *paymentModel.Refund, providerReference string, adminID uuid.UUID) (payment paymentModel.Payment, err error) {
+	err = s.DB.WithTransaction(func(tx *sqlx.Tx, e chan error) {
+		order, err := s.OrderRepo.GetOrderByIDForUpdate(ctx, tx, refund.OrderID.String())
+		if err != nil {
+			e <- err
+			return
+		}
+		payment, err = s.Repo.GetPaymentByIDForUpdate(ctx, tx, refund.PaymentID.String())
+		if err != nil {
+			e <- err
+			return
+		}
+
+		refund.Succeed(providerReference)
+		refund.UpdatedBy = adminID
+		err = s.Repo.UpdateRefundTx(ctx, tx, refund)
+		if err != nil {
+			e <- err
+			return
+		}
+
+		_, succeeded, err := s.Repo.GetRefundTotalsByPaymentIDTx(ctx, tx, refund.PaymentID.String())
+		if err != nil {
+			e <- err
+			return
+		}
+		payment.ApplyRefunds(succeeded)
+		payment.UpdatedBy = adminID
+		err = s.Repo.UpdatePaymentTx(ctx, tx, &payment)
+		if err != nil {
+			e <- err
+			return
+		}
+
+		restocked := order.Status == int(orderModel.OrderCancelledStatus) || order.Status == int(orderModel.OrderExpiredStatus)
+		if refund.Restock && !restocked {
+			for _, item := range refund.Items {
+				err = s.ProductRepo.AdjustProductStockTx(ctx, tx, item.ProductID.String(), item.Quantity)
+				if err != nil {
+					e <- err
+					return
+				}
+			}
+		}
+
+		if payment.Status == int(paymentModel.Refunded) {
+			err = s.refundOrder(ctx, tx, &order, adminID)
+			if err != nil {
+				e <- err
+				return
+			}
+		}
+		e <- nil
+	})
+	return
+}
+
+// refundOrder moves the locked order of a fully refunded payment to
+// refunded when its current status allows it, e.g. a cancelled order stays
+// cancelled.
+func (s *PaymentServiceImpl) refundOrder(ctx context.Context, tx *sqlx.Tx, order *orderModel.Order, adminID uuid.UUID) (err error) {
+	if !orderModel.OrderStatus(order.Status).CanTransitionTo(orderModel.OrderRefundedStatus) {
+		return nil
+	}
+	history, err := order.TransitionTo(orderModel.OrderRefundedStatus, adminID)
+	if err != nil {
+		return
+	}
+	return s.OrderRepo.UpdateOrderStatusTx(ctx, tx, order, &history)
+}
+
+func (s *PaymentServiceImpl) failRefund(ctx context.Context, refund *paymentModel.Refund, adminID uuid.UUID) {
+	refund.Fail()
+	refund.UpdatedBy = adminID
+	err := s.DB.WithTransaction(func(tx *sqlx.Tx, e chan error) {
+		e <- s.Repo.UpdateRefundTx(ctx, tx, refund)
+	})
+	if err != nil {
+		log.Error().Err(err).Str("refund", refund.ID.String()).Msg("[failRefund] Failed UpdateRefundTx")
+	}
+}
+
+// ListRefunds returns the refund ledger of a payment.
+func (s *PaymentServiceImpl) ListRefunds(ctx context.Context, paymentID string) (res dto.RefundListResponse, err error) {
+	payment, err := s.Repo.GetPaymentByID(ctx, paymentID)
+	if err != nil {
+		log.Error().Err(err).Msg("[ListRefunds] Failed GetPaymentByID")
+		if err == sql.ErrNoRows {
+			err = failure.NotFound("payment")
+		}
+		return
+	}
+	refunds, err := s.Repo.GetRefundsByPaymentID(ctx, paymentID)
+	if err != nil {
+		log.Error().Err(err).Msg("[ListRefunds] Failed GetRefundsByPaymentID")
+		return
+	}
+	items, err := s.Repo.GetRefundItemsByPaymentID(ctx, paymentID)
+	if err != nil {
+		log.Error().Err(err).Msg("[ListRefunds] Failed GetRefundItemsByPaymentID")
+		return
+	}
+
+	var refunded, committed money.Amount
+	res.Refunds = make([]dto.RefundResponse, 0, len(refunds))
+	for _, refund := range refunds {
+		for _, item := range items {
+			if item.RefundID == refund.ID {
+				refund.Items = append(refund.Items, item)
+			}
+		}
+		switch paymentModel.RefundStatus(refund.Status) {
+		case paymentModel.RefundSucceeded:
+			refunded += refund.Amount
+			committed += refund.Amount
+		case paymentModel.RefundRequested:
+			committed += refund.Amount
+		}
+		res.Refunds = append(res.Refunds, dto.NewRefundResponse(refund))
+	}
+	res.TotalRefunded = refunded
+	if payment.IsRefundable() {
+		res.RefundableAmount = payment.TotalPrice - committed
+	}
+	return
+}
@@ -2,37 +2,69 @@ package service
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/azka-zaydan/synapsis-test/configs"
 	"github.com/azka-zaydan/synapsis-test/infras"
 	orderModel "github.com/azka-zaydan/synapsis-test/internal/domain/order/model"
 	orderRepo "github.com/azka-zaydan/synapsis-test/internal/domain/order/repository"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/payment/gateway"
+	paymentModel "github.com/azka-zaydan/synapsis-test/internal/domain/payment/model"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/payment/model/dto"
 
 	"github.com/azka-zaydan/synapsis-test/internal/domain/payment/repository"
+	productRepo "github.com/azka-zaydan/synapsis-test/internal/domain/product/repository"
+	reservationModel "github.com/azka-zaydan/synapsis-test/internal/domain/reservation/model"
+	reservationRepo "github.com/azka-zaydan/synapsis-test/internal/domain/reservation/repository"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/gofrs/uuid"
 	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
 
 	"github.com/rs/zerolog/log"
 )
 
+// defaultWebhookTolerance is how far a webhook timestamp may drift from now
+// when no tolerance is configured.
+const defaultWebhookTolerance = 5 * time.Minute
+
 type PaymentService interface {
-	Pay(ctx context.Context, req dto.PayRequest) (res dto.PaymentResponse, err error)
+	Pay(ctx context.Context, req dto.PayRequest, userID uuid.UUID) (res dto.PaymentResponse, err error)
+	HandleWebhook(ctx context.Context, payload []byte, signature string, timestamp string) (err error)
+	Refund(ctx context.Context, paymentID string, req dto.RefundRequest, adminID uuid.UUID) (res dto.RefundResultResponse, err error)
+	ListRefunds(ctx context.Context, paymentID string) (res dto.RefundListResponse, err error)
 }
 
 type PaymentServiceImpl struct {
-	Repo      repository.PaymentRepository
-	Redis     *infras.Redis
-	config    *configs.Config
-	OrderRepo orderRepo.OrderRepository
+	Repo            repository.PaymentRepository
+	DB              *infras.MySQLConn
+	Redis           *infras.Redis
+	config          *configs.Config
+	OrderRepo       orderRepo.OrderRepository
+	ReservationRepo reservationRepo.ReservationRepository
+	ProductRepo     productRepo.ProductRepository
+	Gateways        *gateway.Registry
 }
 
-func ProvidePaymentServiceImpl(repo repository.PaymentRepository, redis *infras.Redis, config *configs.Config, orderRepo orderRepo.OrderRepository) *PaymentServiceImpl {
+func ProvidePaymentServiceImpl(repo repository.PaymentRepository, db *infras.MySQLConn, redis *infras.Redis, config *configs.Config, orderRepo orderRepo.OrderRepository, reservationRepo reservationRepo.ReservationRepository, productRepo productRepo.ProductRepository, gateways *gateway.Registry) *PaymentServiceImpl {
 	return &PaymentServiceImpl{
-		Redis:     redis,
-		Repo:      repo,
-		config:    config,
-		OrderRepo: orderRepo,
+		Gateways:        gateways,
+		DB:              db,
+		Redis:           redis,
+		Repo:            repo,
+		config:          config,
+		OrderRepo:       orderRepo,
+		ReservationRepo: reservationRepo,
+		ProductRepo:     productRepo,
 	}
 }
 
@@ -42,6 +74,7 @@ func (s *PaymentServiceImpl) CreatePayment(ctx context.Context, req dto.CreatePa
 		log.Error().Err(err).Msg("[CreatePayment] Failed creating model")
 		return
 	}
+	mod.Currency = s.config.DefaultCurrency()
 	err = s.Repo.CreatePayment(ctx, &mod)
 	if err != nil {
 		log.Error().Err(err).Msg("[CreatePayment] Failed creating payment")
@@ -50,34 +83,358 @@ func (s *PaymentServiceImpl) CreatePayment(ctx context.Context, req dto.CreatePa
 	return dto.NewPaymentResponse(mod), nil
 }
 
-func (s *PaymentServiceImpl) Pay(ctx context.Context, req dto.PayRequest) (res dto.PaymentResponse, err error) {
+func (s *PaymentServiceImpl) Pay(ctx context.Context, req dto.PayRequest, userID uuid.UUID) (res dto.PaymentResponse, err error) {
 	mod, err := s.Repo.GetPaymentByOrderID(ctx, req.OrderID)
 	if err != nil {
 		log.Error().Err(err).Msg("[Pay] Failed GetPaymentByOrderID")
+		if err == sql.ErrNoRows {
+			err = failure.NotFound("payment")
+		}
+		return
+	}
+	if mod.UserID != userID {
+		err = failure.Forbidden("payment belongs to another user")
+		log.Error().Err(err).Msg("[Pay] Payment Not Owned")
+		return
+	}
+	if mod.Status != int(paymentModel.Unpaid) {
+		err = failure.Conflict("pay", "payment", fmt.Sprintf("payment is %s", paymentModel.PaymentStatus(mod.Status)))
+		log.Error().Err(err).Msg("[Pay] Payment Not Payable")
+		return
+	}
+	if !s.isAllowedMethod(req.PaymentMethod) {
+		err = failure.UnprocessableEntity(fmt.Sprintf("paymentMethod must be one of: %s", strings.Join(s.allowedMethods(), ", ")))
+		log.Error().Err(err).Msg("[Pay] Invalid Payment Method")
+		return
+	}
+	if req.Amount != mod.TotalPrice {
+		err = failure.UnprocessableEntity("amount does not match the payment total")
+		log.Error().Err(err).Msg("[Pay] Amount Mismatch")
 		return
 	}
-
-	mod.Pay()
 
 	order, err := s.OrderRepo.GetOrderByID(ctx, mod.OrderID.String())
 	if err != nil {
 		log.Error().Err(err).Msg("[Pay] Failed GetOrderByID")
 		return
 	}
-	order.Status = int(orderModel.OrderPaidStatus)
-	order.PaymentAt = null.TimeFrom(time.Now())
+	// refuse before charging anything if the order can no longer be paid
+	if _, err = order.TransitionTo(orderModel.OrderPaidStatus, mod.UserID); err != nil {
+		log.Error().Err(err).Msg("[Pay] Failed TransitionTo")
+		return
+	}
+	if order.TotalPrice != mod.TotalPrice {
+		err = failure.UnprocessableEntity("payment total does not match the order total")
+		log.Error().Err(err).Msg("[Pay] Order Total Mismatch")
+		return
+	}
 
-	err = s.Repo.UpdatePayment(ctx, &mod)
+	gw, err := s.Gateways.Get(req.PaymentMethod)
 	if err != nil {
-		log.Error().Err(err).Msg("[Pay] Failed UpdatePayment")
+		log.Error().Err(err).Msg("[Pay] Failed Getting Gateway")
+		return
+	}
+
+	// claim the payment so concurrent requests cannot charge it twice
+	claimed, err := s.Repo.UpdatePaymentStatusFrom(ctx, mod.ID.String(), paymentModel.Unpaid, paymentModel.Pending)
+	if err != nil {
+		log.Error().Err(err).Msg("[Pay] Failed Claiming Payment")
+		return
+	}
+	if !claimed {
+		err = failure.Conflict("pay", "payment", "payment is already being processed")
+		log.Error().Err(err).Msg("[Pay] Payment Already Claimed")
 		return
 	}
 
-	err = s.OrderRepo.UpdateOrder(ctx, &order)
+	intent, err := s.charge(ctx, gw, mod, req.PaymentToken)
 	if err != nil {
-		log.Error().Err(err).Msg("[Pay] Failed UpdateOrder")
+		log.Error().Err(err).Msg("[Pay] Failed charge")
+		s.releaseClaim(ctx, mod)
+		return
+	}
+	mod.PaymentMethod = gw.Method()
+	mod.ProviderReference = null.StringFrom(intent.ID)
+
+	switch intent.Status {
+	case gateway.IntentSucceeded:
+		err = s.completePayment(ctx, &mod)
+		if err != nil {
+			log.Error().Err(err).Msg("[Pay] Failed completePayment")
+			s.reverseCharge(ctx, gw, intent)
+			s.releaseClaim(ctx, mod)
+			return
+		}
+	case gateway.IntentPending:
+		mod.MarkPending()
+		err = s.Repo.UpdatePayment(ctx, &mod)
+		if err != nil {
+			log.Error().Err(err).Msg("[Pay] Failed UpdatePayment")
+			return
+		}
+	default:
+		err = failure.PaymentRequired(fmt.Sprintf("payment was declined: %s", intent.DeclineReason))
+		log.Error().Err(err).Msg("[Pay] Charge Declined")
+		s.releaseClaim(ctx, mod)
 		return
 	}
 
 	return dto.NewPaymentResponse(mod), nil
 }
+
+// releaseClaim hands a payment whose charge did not go through back to the
+// customer so it can be paid again.
+func (s *PaymentServiceImpl) releaseClaim(ctx context.Context, mod paymentModel.Payment) {
+	_, err := s.Repo.UpdatePaymentStatusFrom(ctx, mod.ID.String(), paymentModel.Pending, paymentModel.Unpaid)
+	if err != nil {
+		log.Error().Err(err).Str("payment", mod.ID.String()).Msg("[releaseClaim] Failed UpdatePaymentStatusFrom")
+	}
+}
+
+// allowedMethods returns the payment methods customers may pay with. When
+// none are configured every registered gateway is allowed.
+func (s *PaymentServiceImpl) allowedMethods() []string {
+	if s.config.Payment.AllowedMethods == "" {
+		return s.Gateways.Methods()
+	}
+	methods := []string{}
+	for _, method := range strings.Split(s.config.Payment.AllowedMethods, ",") {
+		if method = strings.ToLower(strings.TrimSpace(method)); method != "" {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+func (s *PaymentServiceImpl) isAllowedMethod(method string) bool {
+	method = strings.ToLower(strings.TrimSpace(method))
+	if method == "" {
+		return false
+	}
+	for _, allowed := range s.allowedMethods() {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *PaymentServiceImpl) charge(ctx context.Context, gw gateway.PaymentGateway, mod paymentModel.Payment, token string) (intent gateway.Intent, err error) {
+	intent, err = gw.CreateIntent(ctx, gateway.CreateIntentRequest{
+		Reference: mod.ID.String(),
+		Amount:    mod.TotalPrice,
+		Currency:  mod.Currency,
+		Token:     token,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[charge] Failed CreateIntent")
+		return
+	}
+	return gw.Capture(ctx, intent.ID)
+}
+
+// reverseCharge refunds a captured charge whose payment could not be
+// recorded, so the customer is not billed for an order that stays unpaid.
+func (s *PaymentServiceImpl) reverseCharge(ctx context.Context, gw gateway.PaymentGateway, intent gateway.Intent) {
+	_, err := gw.Refund(ctx, intent.ID, intent.Amount)
+	if err != nil {
+		log.Error().Err(err).Str("intent", intent.ID).Msg("[reverseCharge] Failed Refund")
+	}
+}
+
+// completePayment marks a payment settled by its provider as paid, together
+// with its order and stock holds.
+func (s *PaymentServiceImpl) completePayment(ctx context.Context, mod *paymentModel.Payment) (err error) {
+	order, err := s.OrderRepo.GetOrderByID(ctx, mod.OrderID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[completePayment] Failed GetOrderByID")
+		return
+	}
+	history, err := order.TransitionTo(orderModel.OrderPaidStatus, mod.UserID)
+	if err != nil {
+		log.Error().Err(err).Msg("[completePayment] Failed TransitionTo")
+		return
+	}
+	mod.Pay()
+
+	err = s.DB.WithTransaction(func(tx *sqlx.Tx, e chan error) {
+		err := s.confirmReservations(ctx, tx, mod.OrderID.String())
+		if err != nil {
+			e <- err
+			return
+		}
+		err = s.Repo.UpdatePaymentTx(ctx, tx, mod)
+		if err != nil {
+			e <- err
+			return
+		}
+		err = s.OrderRepo.UpdateOrderStatusTx(ctx, tx, &order, &history)
+		if err != nil {
+			e <- err
+			return
+		}
+		e <- nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[completePayment] Failed Pay Transaction")
+		return
+	}
+	return
+}
+
+// confirmReservations turns the stock holds of an order into permanent
+// sales. Paying after a hold has lapsed is refused since its stock may
+// already have been sold to someone else.
+func (s *PaymentServiceImpl) confirmReservations(ctx context.Context, tx *sqlx.Tx, orderId string) (err error) {
+	reservations, err := s.ReservationRepo.GetReservationsByOrderIDForUpdate(ctx, tx, orderId)
+	if err != nil {
+		log.Error().Err(err).Msg("[confirmReservations] Failed GetReservationsByOrderIDForUpdate")
+		return
+	}
+	now := time.Now()
+	for i := range reservations {
+		if reservations[i].Status == int(reservationModel.ReservationConfirmed) {
+			continue
+		}
+		if reservations[i].Status == int(reservationModel.ReservationReleased) || reservations[i].IsExpired(now) {
+			return failure.Conflict("pay", "order", "stock reservation has expired")
+		}
+		reservations[i].Status = int(reservationModel.ReservationConfirmed)
+		err = s.ReservationRepo.UpdateReservationTx(ctx, tx, &reservations[i])
+		if err != nil {
+			log.Error().Err(err).Msg("[confirmReservations] Failed UpdateReservationTx")
+			return
+		}
+	}
+	return
+}
+
+// HandleWebhook applies an asynchronous payment notification. The payload
+// must be signed with the shared webhook secret, carry a fresh timestamp and
+// an event ID that has not been seen before.
+func (s *PaymentServiceImpl) HandleWebhook(ctx context.Context, payload []byte, signature string, timestamp string) (err error) {
+	err = s.verifyWebhookSignature(payload, signature, timestamp)
+	if err != nil {
+		log.Error().Err(err).Msg("[HandleWebhook] Failed verifyWebhookSignature")
+		return
+	}
+
+	var event dto.WebhookEvent
+	err = json.Unmarshal(payload, &event)
+	if err != nil {
+		log.Error().Err(err).Msg("[HandleWebhook] Failed Unmarshal Event")
+		return failure.BadRequest(err)
+	}
+	if event.ID == "" || event.Data.IntentID == "" {
+		return failure.BadRequestFromString("webhook event is missing its id or intent id")
+	}
+
+	key := fmt.Sprintf("webhook:event:{%s}", event.ID)
+	claimed, err := s.Redis.Client.SetNX(ctx, key, event.Type, 2*s.webhookTolerance()).Result()
+	if err != nil {
+		log.Error().Err(err).Msg("[HandleWebhook] Failed Claiming Event")
+		return
+	}
+	if !claimed {
+		return failure.Conflict("webhook", "event", "already processed")
+	}
+
+	err = s.applyWebhookEvent(ctx, event)
+	if err != nil {
+		log.Error().Err(err).Msg("[HandleWebhook] Failed applyWebhookEvent")
+		// let the provider deliver the event again
+		s.Redis.Client.Del(ctx, key)
+		return
+	}
+	return
+}
+
+func (s *PaymentServiceImpl) webhookTolerance() time.Duration {
+	if s.config.Payment.Webhook.Tolerance > 0 {
+		return s.config.Payment.Webhook.Tolerance
+	}
+	return defaultWebhookTolerance
+}
+
+// verifyWebhookSignature checks signature against an HMAC-SHA256 of
+// "<timestamp>.<payload>" keyed with the webhook secret.
+func (s *PaymentServiceImpl) verifyWebhookSignature(payload []byte, signature string, timestamp string) (err error) {
+	secret := s.config.Payment.Webhook.Secret
+	if secret == "" {
+		return failure.Unauthorized("webhook secret is not configured")
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return failure.Unauthorized("invalid webhook timestamp")
+	}
+	age := time.Since(time.Unix(ts, 0))
+	if tolerance := s.webhookTolerance(); age > tolerance || age < -tolerance {
+		return failure.Unauthorized("webhook timestamp is outside the tolerance window")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return failure.Unauthorized("invalid webhook signature")
+	}
+	return nil
+}
+
+func (s *PaymentServiceImpl) applyWebhookEvent(ctx context.Context, event dto.WebhookEvent) (err error) {
+	mod, err := s.Repo.GetPaymentByProviderReference(ctx, event.Data.IntentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = failure.NotFound("payment")
+		}
+		return
+	}
+
+	switch event.Type {
+	case dto.WebhookEventPaymentSucceeded:
+		if mod.Status == int(paymentModel.Paid) {
+			return nil
+		}
+		if event.Data.Amount != mod.TotalPrice {
+			return failure.UnprocessableEntity("webhook amount does not match the payment")
+		}
+		intent := gateway.Intent{ID: event.Data.IntentID, Amount: event.Data.Amount}
+		if mod.Status != int(paymentModel.Pending) && mod.Status != int(paymentModel.Unpaid) {
+			// the order was cancelled or expired while the charge was pending
+			s.refundLateCharge(ctx, mod, intent)
+			return nil
+		}
+		err = s.completePayment(ctx, &mod)
+		if err != nil {
+			if failure.GetCode(err) == http.StatusConflict {
+				s.refundLateCharge(ctx, mod, intent)
+				return nil
+			}
+			return
+		}
+	case dto.WebhookEventPaymentFailed:
+		if mod.Status != int(paymentModel.Pending) {
+			return nil
+		}
+		// the customer may try again with another instrument
+		mod.Status = int(paymentModel.Unpaid)
+		err = s.Repo.UpdatePayment(ctx, &mod)
+		if err != nil {
+			return
+		}
+	default:
+		log.Info().Str("type", event.Type).Msg("[applyWebhookEvent] Ignoring Event")
+	}
+	return nil
+}
+
+func (s *PaymentServiceImpl) refundLateCharge(ctx context.Context, mod paymentModel.Payment, intent gateway.Intent) {
+	gw, err := s.Gateways.Get(mod.PaymentMethod)
+	if err != nil {
+		log.Error().Err(err).Str("intent", intent.ID).Msg("[refundLateCharge] Failed Getting Gateway")
+		return
+	}
+	s.reverseCharge(ctx, gw, intent)
+}
@@ -1,24 +1,41 @@
 package dto
 
 import (
+	"errors"
+
 	"github.com/azka-zaydan/synapsis-test/internal/domain/product/model"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
 	"github.com/gofrs/uuid"
 )
 
 type ProductCreateRequest struct {
-	CategoryID  string  `json:"categoryId"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Stock       int     `json:"stock"`
-	CreatedBy   string  `json:"createdBy"`
+	CategoryID  string       `json:"categoryId"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Price       money.Amount `json:"price"`
+	// Currency of Price, the configured default currency when empty.
+	Currency string `json:"currency"`
+	Stock    int    `json:"stock"`
+	// Weight is the shipping weight of one unit in grams.
+	Weight    int    `json:"weight"`
+	CreatedBy string `json:"createdBy"`
 }
 
-func (d *ProductCreateRequest) ToModel() (res model.Product, err error) {
+func (d *ProductCreateRequest) ToModel(defaultCurrency money.Currency) (res model.Product, err error) {
 	id, err := uuid.NewV4()
 	if err != nil {
 		return
 	}
+	currency := defaultCurrency
+	if d.Currency != "" {
+		currency, err = money.ParseCurrency(d.Currency)
+		if err != nil {
+			return
+		}
+	}
+	if d.Weight < 0 {
+		return res, errors.New("weight must not be negative")
+	}
 	catId, err := uuid.FromString(d.CategoryID)
 	if err != nil {
 		return
@@ -29,7 +46,9 @@ func (d *ProductCreateRequest) ToModel() (res model.Product, err error) {
 		Name:        d.Name,
 		Description: d.Description,
 		Price:       d.Price,
+		Currency:    currency,
 		Stock:       d.Stock,
+		Weight:      d.Weight,
 		CreatedBy:   d.CreatedBy,
 		UpdatedBy:   d.CreatedBy,
 	}, nil
@@ -0,0 +1,194 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/product/model"
+	"github.com/guregu/null"
+)
+
+// cursor is what the opaque cursors of cursor pagination encode: the sort
+// they were made for, the sort keys of the product to continue from and
+// the direction to continue in.
+type cursor struct {
+	Sort     []string      `json:"s"`
+	Keys     []interface{} `json:"k"`
+	Backward bool          `json:"b"`
+}
+
+type CursorMetadata struct {
+	PageSize   int         `json:"pageSize"`
+	NextCursor null.String `json:"nextCursor"`
+	PrevCursor null.String `json:"prevCursor"`
+	TotalData  null.Int    `json:"totalData"`
+}
+
+// DecodeCursor sets the keyset filter.Cursor decodes to. It must be called
+// once the fields of filter are transformed to their columns, and fails
+// when the cursor was made for another sort.
+func DecodeCursor(filter *model.Filter) (err error) {
+	if filter.Cursor == "" {
+		return nil
+	}
+	invalid := errors.New("invalid cursor")
+	raw, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+	if err != nil {
+		return invalid
+	}
+	var c cursor
+	err = json.Unmarshal(raw, &c)
+	if err != nil {
+		return invalid
+	}
+	sort := sortSignature(filter.Sort)
+	if len(c.Sort) != len(sort) || len(c.Keys) != len(sort) {
+		return errors.New("cursor does not match the sort")
+	}
+	keys := make([]interface{}, 0, len(c.Keys))
+	for i, v := range sort {
+		if c.Sort[i] != v {
+			return errors.New("cursor does not match the sort")
+		}
+		field, ok := productFieldByDB(filter.Sort[i].Field)
+		if !ok {
+			return invalid
+		}
+		key, err := normalizeKindValue(field.kind, c.Keys[i])
+		if err != nil {
+			return invalid
+		}
+		keys = append(keys, key)
+	}
+	filter.Keyset = &model.Keyset{Keys: keys, Backward: c.Backward}
+	return nil
+}
+
+// NewProductCursorResponse pages data, which holds a product more than the
+// page size when there are more products past the page, in the order of
+// the keyset of filter.
+func NewProductCursorResponse(data []model.Product, filter model.Filter, totalData null.Int) (res ProductFilterResponse, err error) {
+	more := len(data) > filter.PageSize
+	if more {
+		data = data[:filter.PageSize]
+	}
+	backward := filter.Keyset != nil && filter.Keyset.Backward
+	if backward {
+		for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+
+	metadata := CursorMetadata{
+		PageSize:  filter.PageSize,
+		TotalData: totalData,
+	}
+	// There are products after the page when going forward and there are
+	// more of them, or when going back from a cursor.
+	if more || backward {
+		metadata.NextCursor, err = edgeCursor(data, len(data)-1, filter, false)
+		if err != nil {
+			return
+		}
+	}
+	if (backward && more) || (!backward && filter.Keyset != nil) {
+		metadata.PrevCursor, err = edgeCursor(data, 0, filter, true)
+		if err != nil {
+			return
+		}
+	}
+	return ProductFilterResponse{
+		Data:     TransformProductList(data),
+		Metadata: metadata,
+	}, nil
+}
+
+// edgeCursor returns the cursor continuing from the product at i of data,
+// or from the cursor of filter when data is empty.
+func edgeCursor(data []model.Product, i int, filter model.Filter, backward bool) (res null.String, err error) {
+	var keys []interface{}
+	if len(data) == 0 {
+		keys = filter.Keyset.Keys
+	} else {
+		for _, v := range filter.Sort {
+			keys = append(keys, productKey(data[i], v.Field))
+		}
+	}
+	s, err := encodeCursor(cursor{
+		Sort:     sortSignature(filter.Sort),
+		Keys:     keys,
+		Backward: backward,
+	})
+	if err != nil {
+		return
+	}
+	return null.StringFrom(s), nil
+}
+
+func encodeCursor(c cursor) (string, error) {
+	keys := make([]interface{}, 0, len(c.Keys))
+	for _, v := range c.Keys {
+		if t, ok := v.(time.Time); ok {
+			v = t.UTC().Format(time.RFC3339Nano)
+		}
+		keys = append(keys, v)
+	}
+	c.Keys = keys
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func sortSignature(sort []model.SortField) (res []string) {
+	for _, v := range sort {
+		res = append(res, fmt.Sprintf("%s:%s", v.Field, v.Direction))
+	}
+	return
+}
+
+func productFieldByDB(db string) (res productField, ok bool) {
+	for _, field := range productFields {
+		if string(field.db) == db {
+			return field, true
+		}
+	}
+	return res, false
+}
+
+// productKey returns the value of the column db of prod, the way the
+// cursor keeps it.
+func productKey(prod model.Product, db string) interface{} {
+	switch model.ProductDBField(db) {
+	case model.Id:
+		return prod.ID.String()
+	case model.CategoryID:
+		return prod.CategoryID.String()
+	case model.Name:
+		return prod.Name
+	case model.Description:
+		return prod.Description
+	case model.Price:
+		return prod.Price.String()
+	case model.Currency:
+		return prod.Currency.String()
+	case model.Stock:
+		return prod.Stock
+	case model.Weight:
+		return prod.Weight
+	case model.CreatedBy:
+		return prod.CreatedBy
+	case model.MetaCreatedAt:
+		return prod.MetaCreatedAt
+	case model.UpdatedBy:
+		return prod.UpdatedBy
+	case model.MetaUpdatedAt:
+		return prod.MetaUpdatedAt
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,357 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/product/model"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+)
+
+const (
+	// maxFilterDepth bounds how deep filter groups nest.
+	maxFilterDepth = 5
+	// maxFilterValues bounds the values of an in or nin filter.
+	maxFilterValues = 100
+)
+
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindUUID
+	kindAmount
+	kindInt
+	kindTime
+)
+
+type productField struct {
+	db   model.ProductDBField
+	kind fieldKind
+}
+
+// productFields are the fields products can be filtered and sorted on.
+var productFields = map[ProductJSONField]productField{
+	Id:            {model.Id, kindUUID},
+	CategoryID:    {model.CategoryID, kindUUID},
+	Name:          {model.Name, kindString},
+	Description:   {model.Description, kindString},
+	Price:         {model.Price, kindAmount},
+	Currency:      {model.Currency, kindString},
+	Stock:         {model.Stock, kindInt},
+	Weight:        {model.Weight, kindInt},
+	CreatedBy:     {model.CreatedBy, kindString},
+	MetaCreatedAt: {model.MetaCreatedAt, kindTime},
+	UpdatedBy:     {model.UpdatedBy, kindString},
+	MetaUpdatedAt: {model.MetaUpdatedAt, kindTime},
+	DeletedBy:     {model.DeletedBy, kindString},
+	MetaDeletedAt: {model.MetaDeletedAt, kindTime},
+}
+
+// nullableFields cannot be paged through with a cursor, NULL has no place
+// in the order of keys.
+var nullableFields = map[ProductJSONField]bool{
+	DeletedBy:     true,
+	MetaDeletedAt: true,
+}
+
+// kindOperators are the operators each kind of field can be filtered with.
+var kindOperators = map[fieldKind][]string{
+	kindString: {model.OperatorEq, model.OperatorNot, model.OperatorLike, model.OperatorIn, model.OperatorNin, model.OperatorIsNull, model.OperatorNotNull},
+	kindUUID:   {model.OperatorEq, model.OperatorNot, model.OperatorIn, model.OperatorNin, model.OperatorIsNull, model.OperatorNotNull},
+	kindAmount: {model.OperatorEq, model.OperatorNot, model.OperatorGt, model.OperatorGte, model.OperatorLt, model.OperatorLte, model.OperatorBetween, model.OperatorIn, model.OperatorNin, model.OperatorIsNull, model.OperatorNotNull},
+	kindInt:    {model.OperatorEq, model.OperatorNot, model.OperatorGt, model.OperatorGte, model.OperatorLt, model.OperatorLte, model.OperatorBetween, model.OperatorIn, model.OperatorNin, model.OperatorIsNull, model.OperatorNotNull},
+	kindTime:   {model.OperatorEq, model.OperatorNot, model.OperatorGt, model.OperatorGte, model.OperatorLt, model.OperatorLte, model.OperatorBetween, model.OperatorIsNull, model.OperatorNotNull},
+}
+
+// ValidateAndSetDefaultFilter checks every field, operator and value of the
+// filter and turns the values into the type of their field: amounts for
+// price, integers for stock and weight, times for the timestamps, which are
+// given in RFC 3339.
+func ValidateAndSetDefaultFilter(filter *model.Filter) (err error) {
+	err = validateFilterFields(filter.FilterField)
+	if err != nil {
+		return err
+	}
+	err = validateFilterGroups(filter.Groups, 1)
+	if err != nil {
+		return err
+	}
+	err = validateSort(filter.Sort)
+	if err != nil {
+		return err
+	}
+	err = validatePagination(filter)
+	if err != nil {
+		return err
+	}
+
+	if filter.Page == 0 && filter.Pagination == model.PaginationPage {
+		filter.Page = 1
+	}
+
+	if filter.PageSize == 0 {
+		filter.PageSize = 10
+	}
+
+	return nil
+}
+
+// validatePagination settles the pagination mode. A cursor implies cursor
+// pagination, which sorts by id last so every product has its own key.
+func validatePagination(filter *model.Filter) error {
+	if filter.Pagination == "" {
+		filter.Pagination = model.PaginationPage
+		if filter.Cursor != "" {
+			filter.Pagination = model.PaginationCursor
+		}
+	}
+	switch filter.Pagination {
+	case model.PaginationPage:
+		if filter.Cursor != "" {
+			return errors.New("cursor requires cursor pagination")
+		}
+		return nil
+	case model.PaginationCursor:
+	default:
+		return errors.New("invalid pagination: " + filter.Pagination)
+	}
+	if filter.Page != 0 {
+		return errors.New("page cannot be used with cursor pagination")
+	}
+	if filter.PageSize < 0 {
+		return errors.New("pageSize must not be negative")
+	}
+	var byID bool
+	for _, v := range filter.Sort {
+		if nullableFields[ProductJSONField(v.Field)] {
+			return errors.New("cursor pagination cannot sort by " + v.Field)
+		}
+		byID = byID || v.Field == string(Id)
+	}
+	if !byID {
+		filter.Sort = append(filter.Sort, model.SortField{Field: string(Id), Direction: model.SortAsc})
+	}
+	return nil
+}
+
+func validateFilterGroups(groups []model.FilterGroup, depth int) (err error) {
+	if len(groups) > 0 && depth > maxFilterDepth {
+		return fmt.Errorf("filter groups must not nest deeper than %d", maxFilterDepth)
+	}
+	for _, group := range groups {
+		if group.Logic != model.LogicAnd && group.Logic != model.LogicOr {
+			return errors.New("invalid group logic: " + group.Logic)
+		}
+		err = validateFilterFields(group.FilterField)
+		if err != nil {
+			return err
+		}
+		err = validateFilterGroups(group.Groups, depth+1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateFilterFields(fields []model.FilterField) (err error) {
+	for i, v := range fields {
+		field, err := validateFilterField(v.Field)
+		if err != nil {
+			return err
+		}
+		err = validateOperator(field.kind, v.Operator)
+		if err != nil {
+			return err
+		}
+		fields[i].Value, err = normalizeFilterValue(field.kind, v.Operator, v.Value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", v.Field, err)
+		}
+	}
+	return nil
+}
+
+func validateFilterField(field string) (res productField, err error) {
+	res, ok := productFields[ProductJSONField(field)]
+	if !ok {
+		return res, errors.New("invalid filter field: " + field)
+	}
+	return res, nil
+}
+
+func validateOperator(kind fieldKind, operator string) error {
+	for _, allowed := range kindOperators[kind] {
+		if operator == allowed {
+			return nil
+		}
+	}
+	return errors.New("invalid operator: " + operator)
+}
+
+func validateSort(sort []model.SortField) (err error) {
+	seen := make(map[string]bool)
+	for i, v := range sort {
+		_, err := validateFilterField(v.Field)
+		if err != nil {
+			return errors.New("invalid sort field: " + v.Field)
+		}
+		if seen[v.Field] {
+			return errors.New("duplicate sort field: " + v.Field)
+		}
+		seen[v.Field] = true
+		direction := strings.ToLower(v.Direction)
+		switch direction {
+		case "":
+			direction = model.SortAsc
+		case model.SortAsc, model.SortDesc:
+		default:
+			return errors.New("invalid sort direction: " + v.Direction)
+		}
+		sort[i].Direction = direction
+	}
+	return nil
+}
+
+// normalizeFilterValue returns the value the operator takes, made of values
+// of the kind of the field.
+func normalizeFilterValue(kind fieldKind, operator string, value interface{}) (res interface{}, err error) {
+	switch operator {
+	case model.OperatorIsNull, model.OperatorNotNull:
+		return nil, nil
+	case model.OperatorBetween:
+		values, ok := value.([]interface{})
+		if !ok || len(values) != 2 {
+			return nil, errors.New("between takes a lower and an upper bound")
+		}
+		return normalizeFilterValues(kind, values)
+	case model.OperatorIn, model.OperatorNin:
+		values, ok := value.([]interface{})
+		if !ok || len(values) == 0 {
+			return nil, errors.New(operator + " takes a list of values")
+		}
+		if len(values) > maxFilterValues {
+			return nil, fmt.Errorf("%s takes at most %d values", operator, maxFilterValues)
+		}
+		return normalizeFilterValues(kind, values)
+	case model.OperatorLike:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("must be a string")
+		}
+		return s, nil
+	default:
+		return normalizeKindValue(kind, value)
+	}
+}
+
+func normalizeFilterValues(kind fieldKind, values []interface{}) (res []interface{}, err error) {
+	res = make([]interface{}, 0, len(values))
+	for _, value := range values {
+		normalized, err := normalizeKindValue(kind, value)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, normalized)
+	}
+	return res, nil
+}
+
+func normalizeKindValue(kind fieldKind, value interface{}) (res interface{}, err error) {
+	switch kind {
+	case kindString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("must be a string")
+		}
+		return s, nil
+	case kindUUID:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("must be a uuid")
+		}
+		id, err := uuid.FromString(s)
+		if err != nil {
+			return nil, errors.New("must be a uuid")
+		}
+		return id.String(), nil
+	case kindAmount:
+		switch v := value.(type) {
+		case float64:
+			return money.Parse(strconv.FormatFloat(v, 'f', -1, 64))
+		case string:
+			return money.Parse(v)
+		default:
+			return nil, errors.New("must be an amount")
+		}
+	case kindInt:
+		v, ok := value.(float64)
+		if !ok || v != math.Trunc(v) {
+			return nil, errors.New("must be an integer")
+		}
+		return int64(v), nil
+	case kindTime:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("must be an RFC 3339 timestamp")
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, errors.New("must be an RFC 3339 timestamp")
+		}
+		return t, nil
+	default:
+		return nil, errors.New("unsupported field")
+	}
+}
+
+// TransformToDBField renames the fields of the filter to their columns.
+func TransformToDBField(filter *model.Filter) (err error) {
+	err = transformFilterFields(filter.FilterField)
+	if err != nil {
+		return err
+	}
+	err = transformFilterGroups(filter.Groups)
+	if err != nil {
+		return err
+	}
+	for i, v := range filter.Sort {
+		field, err := validateFilterField(v.Field)
+		if err != nil {
+			return err
+		}
+		filter.Sort[i].Field = string(field.db)
+	}
+	return nil
+}
+
+func transformFilterGroups(groups []model.FilterGroup) (err error) {
+	for _, group := range groups {
+		err = transformFilterFields(group.FilterField)
+		if err != nil {
+			return err
+		}
+		err = transformFilterGroups(group.Groups)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func transformFilterFields(fields []model.FilterField) (err error) {
+	for i, v := range fields {
+		field, err := validateFilterField(v.Field)
+		if err != nil {
+			return err
+		}
+		fields[i].Field = string(field.db)
+	}
+	return nil
+}
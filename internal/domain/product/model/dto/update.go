@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/product/model"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+)
+
+// ProductUpdateRequest replaces the details of a product.
+type ProductUpdateRequest struct {
+	CategoryID  string       `json:"categoryId"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Price       money.Amount `json:"price"`
+	// Currency of Price, the configured default currency when empty.
+	Currency string `json:"currency"`
+	Stock    int    `json:"stock"`
+	// Weight is the shipping weight of one unit in grams.
+	Weight int `json:"weight"`
+}
+
+// ProductPatchRequest changes the details of a product it carries, leaving
+// the others as they are.
+type ProductPatchRequest struct {
+	CategoryID  *string       `json:"categoryId"`
+	Name        *string       `json:"name"`
+	Description *string       `json:"description"`
+	Price       *money.Amount `json:"price"`
+	Currency    *string       `json:"currency"`
+	Stock       *int          `json:"stock"`
+	Weight      *int          `json:"weight"`
+}
+
+func (d *ProductUpdateRequest) Apply(prod *model.Product, defaultCurrency money.Currency, by string) (err error) {
+	if strings.TrimSpace(d.Name) == "" {
+		return errors.New("name must not be empty")
+	}
+	if d.Price < 0 {
+		return errors.New("price must not be negative")
+	}
+	if d.Stock < 0 {
+		return errors.New("stock must not be negative")
+	}
+	if d.Weight < 0 {
+		return errors.New("weight must not be negative")
+	}
+	currency := defaultCurrency
+	if d.Currency != "" {
+		currency, err = money.ParseCurrency(d.Currency)
+		if err != nil {
+			return
+		}
+	}
+	catId, err := uuid.FromString(d.CategoryID)
+	if err != nil {
+		return
+	}
+	prod.CategoryID = catId
+	prod.Name = d.Name
+	prod.Description = d.Description
+	prod.Price = d.Price
+	prod.Currency = currency
+	prod.Stock = d.Stock
+	prod.Weight = d.Weight
+	prod.UpdatedBy = by
+	return nil
+}
+
+// ToUpdateRequest returns the update that sets prod to what it is, changed
+// by the fields of the patch.
+func (d *ProductPatchRequest) ToUpdateRequest(prod model.Product) ProductUpdateRequest {
+	req := ProductUpdateRequest{
+		CategoryID:  prod.CategoryID.String(),
+		Name:        prod.Name,
+		Description: prod.Description,
+		Price:       prod.Price,
+		Currency:    string(prod.Currency),
+		Stock:       prod.Stock,
+		Weight:      prod.Weight,
+	}
+	if d.CategoryID != nil {
+		req.CategoryID = *d.CategoryID
+	}
+	if d.Name != nil {
+		req.Name = *d.Name
+	}
+	if d.Description != nil {
+		req.Description = *d.Description
+	}
+	if d.Price != nil {
+		req.Price = *d.Price
+	}
+	if d.Currency != nil {
+		req.Currency = *d.Currency
+	}
+	if d.Stock != nil {
+		req.Stock = *d.Stock
+	}
+	if d.Weight != nil {
+		req.Weight = *d.Weight
+	}
+	return req
+}
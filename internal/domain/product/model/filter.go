@@ -1,17 +1,85 @@
 package model
 
 var (
-	OperatorEq   = "eq"
-	OperatorNot  = "not"
-	OperatorLike = "like"
+	OperatorEq      = "eq"
+	OperatorNot     = "not"
+	OperatorLike    = "like"
+	OperatorGt      = "gt"
+	OperatorGte     = "gte"
+	OperatorLt      = "lt"
+	OperatorLte     = "lte"
+	OperatorBetween = "between"
+	OperatorIn      = "in"
+	OperatorNin     = "nin"
+	OperatorIsNull  = "isnull"
+	OperatorNotNull = "notnull"
 )
 
+var (
+	LogicAnd = "and"
+	LogicOr  = "or"
+)
+
+var (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+var (
+	PaginationPage   = "page"
+	PaginationCursor = "cursor"
+)
+
+// Filter selects the products matching all of FilterField and Groups.
 type Filter struct {
 	Page        int           `json:"page"`
 	PageSize    int           `json:"pageSize"`
 	FilterField []FilterField `json:"filterFields"`
+	Groups      []FilterGroup `json:"groups"`
+	// Sort orders the products by each field in turn. Products that tie on
+	// all of them are ordered by id.
+	Sort []SortField `json:"sort"`
+	// CategoryID limits the products to a category, and to the categories
+	// below it too when IncludeDescendants is set.
+	CategoryID         string `json:"categoryId"`
+	IncludeDescendants bool   `json:"includeDescendants"`
+	// CategoryIDs are the categories CategoryID resolves to.
+	CategoryIDs []string `json:"-"`
+	// Pagination is page, the default, or cursor. Cursor pagination pages
+	// through Sort from Cursor, the nextCursor or prevCursor of the page
+	// before, and only counts the products when WithTotal is set.
+	Pagination string `json:"pagination"`
+	Cursor     string `json:"cursor"`
+	WithTotal  bool   `json:"withTotal"`
+	// Keyset is what Cursor decodes to.
+	Keyset *Keyset `json:"-"`
+}
+
+// Keyset continues a sorted listing from the product whose sort fields
+// have the values of Keys. Backward lists the products before it instead
+// of after it.
+type Keyset struct {
+	Keys     []interface{}
+	Backward bool
+}
+
+// FilterGroup joins its fields and nested groups with Logic, and or or.
+type FilterGroup struct {
+	Logic       string        `json:"logic"`
+	FilterField []FilterField `json:"filterFields"`
+	Groups      []FilterGroup `json:"groups"`
+}
+
+// SortField orders by Field in Direction, asc or desc. Direction defaults
+// to asc.
+type SortField struct {
+	Field     string `json:"field"`
+	Direction string `json:"direction"`
 }
 
+// FilterField compares Field with Value. Between takes a list of the lower
+// and upper bound, in and nin take a list of values, isnull and notnull
+// take no value.
 type FilterField struct {
 	Field    string      `json:"field"`
 	Operator string      `json:"operator"`
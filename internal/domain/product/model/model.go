@@ -3,6 +3,7 @@ package model
 import (
 	"time"
 
+	"github.com/azka-zaydan/synapsis-test/shared/money"
 	"github.com/gofrs/uuid"
 	"github.com/guregu/null"
 )
@@ -15,7 +16,9 @@ const (
 	Name          ProductDBField = "name"
 	Description   ProductDBField = "description"
 	Price         ProductDBField = "price"
+	Currency      ProductDBField = "currency"
 	Stock         ProductDBField = "stock"
+	Weight        ProductDBField = "weight"
 	CreatedBy     ProductDBField = "created_by"
 	MetaCreatedAt ProductDBField = "meta_created_at"
 	UpdatedBy     ProductDBField = "updated_by"
@@ -25,12 +28,15 @@ const (
 )
 
 type Product struct {
-	ID            uuid.UUID   `db:"id"`
-	CategoryID    uuid.UUID   `db:"category_id"`
-	Name          string      `db:"name"`
-	Description   string      `db:"description"`
-	Price         float64     `db:"price"`
-	Stock         int         `db:"stock"`
+	ID          uuid.UUID      `db:"id"`
+	CategoryID  uuid.UUID      `db:"category_id"`
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	Price       money.Amount   `db:"price"`
+	Currency    money.Currency `db:"currency"`
+	Stock       int            `db:"stock"`
+	// Weight is the shipping weight of one unit in grams.
+	Weight        int         `db:"weight"`
 	CreatedBy     string      `db:"created_by"`
 	MetaCreatedAt time.Time   `db:"meta_created_at"`
 	UpdatedBy     string      `db:"updated_by"`
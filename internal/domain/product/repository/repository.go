@@ -10,14 +10,23 @@ import (
 	"github.com/azka-zaydan/synapsis-test/internal/domain/product/model"
 	"github.com/azka-zaydan/synapsis-test/shared/failure"
 	"github.com/azka-zaydan/synapsis-test/shared/logger"
+	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog/log"
 )
 
 type ProductRepository interface {
 	GetProductByFilter(ctx context.Context, filter *model.Filter) (res []model.Product, totalData int, err error)
+	GetProductByKeyset(ctx context.Context, filter *model.Filter) (res []model.Product, err error)
+	CountProductByFilter(ctx context.Context, filter *model.Filter) (totalData int, err error)
 	CreateProduct(ctx context.Context, data *model.Product) (err error)
 	GetProductByID(ctx context.Context, productId string) (res model.Product, err error)
 	UpdateProduct(ctx context.Context, prod *model.Product) (err error)
+	UpdateProductTx(ctx context.Context, tx *sqlx.Tx, prod *model.Product) (err error)
+	GetProductByIDForUpdate(ctx context.Context, tx *sqlx.Tx, productId string) (res model.Product, err error)
+	AdjustProductStockTx(ctx context.Context, tx *sqlx.Tx, productId string, delta int) (err error)
+	DeleteProduct(ctx context.Context, prod *model.Product) (err error)
+	RestoreProduct(ctx context.Context, prod *model.Product) (err error)
+	CountProductsByCategoryID(ctx context.Context, categoryId string) (res int, err error)
 }
 
 type ProductRepositoryMySQL struct {
@@ -31,29 +40,65 @@ func ProvideProductRepositoryMySQL(db *infras.MySQLConn) *ProductRepositoryMySQL
 }
 
 func (repo *ProductRepositoryMySQL) GetProductByFilter(ctx context.Context, filter *model.Filter) (res []model.Product, totalData int, err error) {
-	query, args, err := repo.buildSQLQuery(countProductQuery, filter)
+	totalData, err = repo.CountProductByFilter(ctx, filter)
+	if err != nil {
+		return
+	}
+
+	query, args, err := repo.buildSQLQuery(productSelectQuery, filter)
 	if err != nil {
 		err = failure.BadRequest(err)
 		log.Error().Err(err).Msg("[GetProductByFilter] failed buildSQLQuery")
 		return
 	}
+	query, err = buildPageQuery(query, filter)
+	if err != nil {
+		err = failure.BadRequest(err)
+		log.Error().Err(err).Msg("[GetProductByFilter] failed buildPageQuery")
+		return
+	}
 
-	err = repo.DB.Read.GetContext(ctx, &totalData, query, args...)
+	err = repo.DB.Read.SelectContext(ctx, &res, query, args...)
 	if err != nil {
-		log.Error().Err(err).Msg("[GetProductByFilter] failed counting total data")
+		log.Error().Err(err).Msg("[GetProductByFilter] failed getting data")
 		return
 	}
+	return
+}
 
-	query, args, err = repo.buildSQLQuery(productSelectQuery, filter)
+func (repo *ProductRepositoryMySQL) GetProductByKeyset(ctx context.Context, filter *model.Filter) (res []model.Product, err error) {
+	query, args, err := repo.buildSQLQuery(productSelectQuery, filter)
 	if err != nil {
 		err = failure.BadRequest(err)
-		log.Error().Err(err).Msg("[GetProductByFilter] failed buildSQLQuery")
+		log.Error().Err(err).Msg("[GetProductByKeyset] failed buildSQLQuery")
+		return
+	}
+	query, keyArgs, err := buildKeysetQuery(query, filter)
+	if err != nil {
+		err = failure.BadRequest(err)
+		log.Error().Err(err).Msg("[GetProductByKeyset] failed buildKeysetQuery")
 		return
 	}
 
-	err = repo.DB.Read.SelectContext(ctx, &res, query, args...)
+	err = repo.DB.Read.SelectContext(ctx, &res, query, append(args, keyArgs...)...)
 	if err != nil {
-		log.Error().Err(err).Msg("[GetProductByFilter] failed getting data")
+		log.Error().Err(err).Msg("[GetProductByKeyset] failed getting data")
+		return
+	}
+	return
+}
+
+func (repo *ProductRepositoryMySQL) CountProductByFilter(ctx context.Context, filter *model.Filter) (totalData int, err error) {
+	query, args, err := repo.buildSQLQuery(countProductQuery, filter)
+	if err != nil {
+		err = failure.BadRequest(err)
+		log.Error().Err(err).Msg("[CountProductByFilter] failed buildSQLQuery")
+		return
+	}
+
+	err = repo.DB.Read.GetContext(ctx, &totalData, query, args...)
+	if err != nil {
+		log.Error().Err(err).Msg("[CountProductByFilter] failed counting total data")
 		return
 	}
 	return
@@ -69,38 +114,196 @@ func (repo *ProductRepositoryMySQL) CreateProduct(ctx context.Context, data *mod
 }
 
 func (repo *ProductRepositoryMySQL) buildSQLQuery(baseQuery string, filter *model.Filter) (string, []interface{}, error) {
-	var conditions []string
-	var args []interface{}
+	where, args, err := buildFilterGroup(model.LogicAnd, filter.FilterField, filter.Groups)
+	if err != nil {
+		return "", nil, err
+	}
+	conditions := []string{"meta_deleted_at IS NULL"}
+	if where != "" {
+		conditions = append(conditions, where)
+	}
+	if len(filter.CategoryIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("category_id IN (%s)", placeholders(len(filter.CategoryIDs))))
+		for _, id := range filter.CategoryIDs {
+			args = append(args, id)
+		}
+	}
 
-	for _, f := range filter.FilterField {
-		var condition string
-		switch f.Operator {
-		case "eq":
-			condition = fmt.Sprintf("%s = ?", f.Field)
-		case "not":
-			condition = fmt.Sprintf("%s != ?", f.Field)
-		case "like":
-			condition = fmt.Sprintf("%s LIKE ?", f.Field)
-			f.Value = fmt.Sprintf("%%%v%%", f.Value)
+	baseQuery = fmt.Sprintf("%s WHERE %s", baseQuery, strings.Join(conditions, " AND "))
+	return baseQuery, args, nil
+}
+
+// buildPageQuery orders the query by the sort of filter, then by id so
+// pages are stable, and limits it to the page of filter.
+func buildPageQuery(baseQuery string, filter *model.Filter) (string, error) {
+	var orders []string
+	var byID bool
+	for _, s := range filter.Sort {
+		var direction string
+		switch s.Direction {
+		case model.SortAsc:
+			direction = "ASC"
+		case model.SortDesc:
+			direction = "DESC"
 		default:
-			return "", nil, errors.New("invalid operator: " + f.Operator)
+			return "", errors.New("invalid sort direction: " + s.Direction)
 		}
-		conditions = append(conditions, condition)
-		args = append(args, f.Value)
+		orders = append(orders, fmt.Sprintf("%s %s", s.Field, direction))
+		byID = byID || s.Field == string(model.Id)
 	}
-
-	if len(conditions) > 0 {
-		baseQuery = fmt.Sprintf("%s WHERE %s", baseQuery, strings.Join(conditions, " AND "))
+	if !byID {
+		orders = append(orders, fmt.Sprintf("%s ASC", model.Id))
 	}
+	baseQuery = fmt.Sprintf("%s ORDER BY %s", baseQuery, strings.Join(orders, ", "))
 
 	if filter.PageSize > 0 {
 		offset := (filter.Page - 1) * filter.PageSize
 		baseQuery = fmt.Sprintf("%s LIMIT %d OFFSET %d", baseQuery, filter.PageSize, offset)
 	}
+	return baseQuery, nil
+}
+
+// buildKeysetQuery continues the query from the keyset of filter in the
+// order of its sort, which ends with id, backward by reversing the order.
+// It takes a product more than the page size, telling whether there are
+// more products past the page.
+func buildKeysetQuery(baseQuery string, filter *model.Filter) (string, []interface{}, error) {
+	var orders []string
+	var ascending []bool
+	for _, s := range filter.Sort {
+		var asc bool
+		switch s.Direction {
+		case model.SortAsc:
+			asc = true
+		case model.SortDesc:
+		default:
+			return "", nil, errors.New("invalid sort direction: " + s.Direction)
+		}
+		if filter.Keyset != nil && filter.Keyset.Backward {
+			asc = !asc
+		}
+		direction := "DESC"
+		if asc {
+			direction = "ASC"
+		}
+		orders = append(orders, fmt.Sprintf("%s %s", s.Field, direction))
+		ascending = append(ascending, asc)
+	}
+
+	var args []interface{}
+	if filter.Keyset != nil {
+		if len(filter.Keyset.Keys) != len(filter.Sort) {
+			return "", nil, errors.New("cursor does not match the sort")
+		}
+		// Products past (a, b) are those with a past the key, or with a
+		// equal to it and b past its key, and so on.
+		var conditions []string
+		for i, s := range filter.Sort {
+			var terms []string
+			for j := 0; j < i; j++ {
+				terms = append(terms, fmt.Sprintf("%s = ?", filter.Sort[j].Field))
+				args = append(args, filter.Keyset.Keys[j])
+			}
+			operator := "<"
+			if ascending[i] {
+				operator = ">"
+			}
+			terms = append(terms, fmt.Sprintf("%s %s ?", s.Field, operator))
+			args = append(args, filter.Keyset.Keys[i])
+			conditions = append(conditions, fmt.Sprintf("(%s)", strings.Join(terms, " AND ")))
+		}
+		baseQuery = fmt.Sprintf("%s AND (%s)", baseQuery, strings.Join(conditions, " OR "))
+	}
 
+	baseQuery = fmt.Sprintf("%s ORDER BY %s LIMIT %d", baseQuery, strings.Join(orders, ", "), filter.PageSize+1)
 	return baseQuery, args, nil
 }
 
+// buildFilterGroup joins the conditions of fields and groups with logic.
+// It returns an empty condition when there is nothing to join.
+func buildFilterGroup(logic string, fields []model.FilterField, groups []model.FilterGroup) (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+	for _, f := range fields {
+		condition, fieldArgs, err := buildFilterCondition(f)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, condition)
+		args = append(args, fieldArgs...)
+	}
+	for _, g := range groups {
+		condition, groupArgs, err := buildFilterGroup(g.Logic, g.FilterField, g.Groups)
+		if err != nil {
+			return "", nil, err
+		}
+		if condition == "" {
+			continue
+		}
+		conditions = append(conditions, condition)
+		args = append(args, groupArgs...)
+	}
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+
+	var separator string
+	switch logic {
+	case model.LogicAnd:
+		separator = " AND "
+	case model.LogicOr:
+		separator = " OR "
+	default:
+		return "", nil, errors.New("invalid group logic: " + logic)
+	}
+	return fmt.Sprintf("(%s)", strings.Join(conditions, separator)), args, nil
+}
+
+func buildFilterCondition(f model.FilterField) (string, []interface{}, error) {
+	switch f.Operator {
+	case model.OperatorEq:
+		return fmt.Sprintf("%s = ?", f.Field), []interface{}{f.Value}, nil
+	case model.OperatorNot:
+		return fmt.Sprintf("%s != ?", f.Field), []interface{}{f.Value}, nil
+	case model.OperatorLike:
+		return fmt.Sprintf("%s LIKE ?", f.Field), []interface{}{fmt.Sprintf("%%%v%%", f.Value)}, nil
+	case model.OperatorGt:
+		return fmt.Sprintf("%s > ?", f.Field), []interface{}{f.Value}, nil
+	case model.OperatorGte:
+		return fmt.Sprintf("%s >= ?", f.Field), []interface{}{f.Value}, nil
+	case model.OperatorLt:
+		return fmt.Sprintf("%s < ?", f.Field), []interface{}{f.Value}, nil
+	case model.OperatorLte:
+		return fmt.Sprintf("%s <= ?", f.Field), []interface{}{f.Value}, nil
+	case model.OperatorBetween:
+		values, ok := f.Value.([]interface{})
+		if !ok || len(values) != 2 {
+			return "", nil, errors.New("between takes a lower and an upper bound")
+		}
+		return fmt.Sprintf("%s BETWEEN ? AND ?", f.Field), values, nil
+	case model.OperatorIn, model.OperatorNin:
+		values, ok := f.Value.([]interface{})
+		if !ok || len(values) == 0 {
+			return "", nil, errors.New(f.Operator + " takes a list of values")
+		}
+		operator := "IN"
+		if f.Operator == model.OperatorNin {
+			operator = "NOT IN"
+		}
+		return fmt.Sprintf("%s %s (%s)", f.Field, operator, placeholders(len(values))), values, nil
+	case model.OperatorIsNull:
+		return fmt.Sprintf("%s IS NULL", f.Field), nil, nil
+	case model.OperatorNotNull:
+		return fmt.Sprintf("%s IS NOT NULL", f.Field), nil, nil
+	default:
+		return "", nil, errors.New("invalid operator: " + f.Operator)
+	}
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
 func (repo *ProductRepositoryMySQL) GetProductByID(ctx context.Context, productId string) (res model.Product, err error) {
 	err = repo.DB.Read.GetContext(ctx, &res, fmt.Sprintf("%s WHERE id = ?", productSelectQuery), productId)
 	if err != nil {
@@ -119,6 +322,60 @@ func (repo *ProductRepositoryMySQL) UpdateProduct(ctx context.Context, prod *mod
 	return
 }
 
+func (repo *ProductRepositoryMySQL) UpdateProductTx(ctx context.Context, tx *sqlx.Tx, prod *model.Product) (err error) {
+	_, err = tx.NamedExecContext(ctx, productUpdateQuery, prod)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *ProductRepositoryMySQL) GetProductByIDForUpdate(ctx context.Context, tx *sqlx.Tx, productId string) (res model.Product, err error) {
+	err = tx.GetContext(ctx, &res, fmt.Sprintf("%s WHERE id = ? FOR UPDATE", productSelectQuery), productId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *ProductRepositoryMySQL) AdjustProductStockTx(ctx context.Context, tx *sqlx.Tx, productId string, delta int) (err error) {
+	_, err = tx.ExecContext(ctx, productAdjustStockQuery, delta, productId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *ProductRepositoryMySQL) DeleteProduct(ctx context.Context, prod *model.Product) (err error) {
+	_, err = repo.DB.Write.NamedExecContext(ctx, productDeleteQuery, prod)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *ProductRepositoryMySQL) RestoreProduct(ctx context.Context, prod *model.Product) (err error) {
+	_, err = repo.DB.Write.NamedExecContext(ctx, productRestoreQuery, prod)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *ProductRepositoryMySQL) CountProductsByCategoryID(ctx context.Context, categoryId string) (res int, err error) {
+	err = repo.DB.Read.GetContext(ctx, &res, fmt.Sprintf("%s WHERE category_id = ? AND meta_deleted_at IS NULL", countProductQuery), categoryId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
 var (
 	productSelectQuery = `SELECT 
         id, 
@@ -126,7 +383,9 @@ var (
         name, 
         description, 
         price, 
+        currency, 
         stock, 
+        weight, 
         created_by, 
         meta_created_at, 
         updated_by, 
@@ -146,7 +405,9 @@ var (
 		name, 
 		description, 
 		price, 
+		currency, 
 		stock, 
+		weight, 
 		created_by, 
 		updated_by
 	) VALUES (
@@ -155,7 +416,9 @@ var (
 		:name, 
 		:description, 
 		:price, 
+		:currency, 
 		:stock, 
+		:weight, 
 		:created_by, 
 		:updated_by
 	)`
@@ -165,8 +428,28 @@ var (
 		name = :name,
 		description = :description,
 		price = :price,
+		currency = :currency,
 		stock = :stock,
+		weight = :weight,
+		updated_by = :updated_by
+	WHERE id = :id AND meta_deleted_at IS NULL
+`
+	productDeleteQuery = `
+	UPDATE product SET
+		deleted_by = :deleted_by,
+		meta_deleted_at = CURRENT_TIMESTAMP
+	WHERE id = :id AND meta_deleted_at IS NULL
+`
+	productRestoreQuery = `
+	UPDATE product SET
+		deleted_by = NULL,
+		meta_deleted_at = NULL,
 		updated_by = :updated_by
-	WHERE id = :id
+	WHERE id = :id AND meta_deleted_at IS NOT NULL
+`
+	productAdjustStockQuery = `
+	UPDATE product SET
+		stock = stock + ?
+	WHERE id = ?
 `
 )
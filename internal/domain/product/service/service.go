@@ -2,25 +2,46 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"net/http"
 
+	"github.com/azka-zaydan/synapsis-test/configs"
+	"github.com/azka-zaydan/synapsis-test/infras"
+
+	categorySvc "github.com/azka-zaydan/synapsis-test/internal/domain/category/service"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/product/model"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/product/model/dto"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/product/repository"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog/log"
 )
 
 type ProductService interface {
 	GetProductByFilter(ctx context.Context, filter model.Filter) (res dto.ProductFilterResponse, err error)
 	CreateProduct(ctx context.Context, req dto.ProductCreateRequest) (res dto.ProductResponse, err error)
+	GetProduct(ctx context.Context, productID string) (res dto.ProductResponse, err error)
+	UpdateProduct(ctx context.Context, productID string, req dto.ProductUpdateRequest, userID uuid.UUID) (res dto.ProductResponse, err error)
+	PatchProduct(ctx context.Context, productID string, req dto.ProductPatchRequest, userID uuid.UUID) (res dto.ProductResponse, err error)
+	DeleteProduct(ctx context.Context, productID string, userID uuid.UUID) (err error)
+	RestoreProduct(ctx context.Context, productID string, userID uuid.UUID) (res dto.ProductResponse, err error)
 }
 
 type ProductServiceImpl struct {
-	Repo repository.ProductRepository
+	Repo        repository.ProductRepository
+	DB          *infras.MySQLConn
+	config      *configs.Config
+	CategorySvc categorySvc.CategoryService
 }
 
-func ProvideProductServiceImpl(repo repository.ProductRepository) *ProductServiceImpl {
+func ProvideProductServiceImpl(repo repository.ProductRepository, db *infras.MySQLConn, config *configs.Config, categorySvc categorySvc.CategoryService) *ProductServiceImpl {
 	return &ProductServiceImpl{
-		Repo: repo,
+		Repo:        repo,
+		DB:          db,
+		config:      config,
+		CategorySvc: categorySvc,
 	}
 }
 
@@ -30,6 +51,15 @@ func (s *ProductServiceImpl) GetProductByFilter(ctx context.Context, filter mode
 		log.Error().Err(err).Msg("[GetProductByFilter] Failed TransformToDBField")
 		return
 	}
+	err = s.resolveCategory(ctx, &filter)
+	if err != nil {
+		log.Error().Err(err).Msg("[GetProductByFilter] Failed resolveCategory")
+		return
+	}
+
+	if filter.Pagination == model.PaginationCursor {
+		return s.getProductByCursor(ctx, filter)
+	}
 
 	data, totalData, err := s.Repo.GetProductByFilter(ctx, &filter)
 
@@ -41,10 +71,43 @@ func (s *ProductServiceImpl) GetProductByFilter(ctx context.Context, filter mode
 	return dto.NewProductFilterResponse(data, filter.Page, filter.PageSize, totalData), nil
 }
 
+func (s *ProductServiceImpl) getProductByCursor(ctx context.Context, filter model.Filter) (res dto.ProductFilterResponse, err error) {
+	err = dto.DecodeCursor(&filter)
+	if err != nil {
+		log.Error().Err(err).Msg("[getProductByCursor] Failed DecodeCursor")
+		return res, failure.BadRequest(err)
+	}
+	data, err := s.Repo.GetProductByKeyset(ctx, &filter)
+	if err != nil {
+		log.Error().Err(err).Msg("[getProductByCursor] Failed GetProductByKeyset")
+		return
+	}
+	var totalData null.Int
+	if filter.WithTotal {
+		total, err := s.Repo.CountProductByFilter(ctx, &filter)
+		if err != nil {
+			log.Error().Err(err).Msg("[getProductByCursor] Failed CountProductByFilter")
+			return res, err
+		}
+		totalData = null.IntFrom(int64(total))
+	}
+	res, err = dto.NewProductCursorResponse(data, filter, totalData)
+	if err != nil {
+		log.Error().Err(err).Msg("[getProductByCursor] Failed NewProductCursorResponse")
+		return
+	}
+	return
+}
+
 func (s *ProductServiceImpl) CreateProduct(ctx context.Context, req dto.ProductCreateRequest) (res dto.ProductResponse, err error) {
-	prod, err := req.ToModel()
+	prod, err := req.ToModel(s.config.DefaultCurrency())
 	if err != nil {
 		log.Error().Err(err).Msg("[CreateProduct] Failed creating model")
+		return res, failure.BadRequest(err)
+	}
+	err = s.checkCategory(ctx, prod.CategoryID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateProduct] Failed checkCategory")
 		return
 	}
 	err = s.Repo.CreateProduct(ctx, &prod)
@@ -54,3 +117,149 @@ func (s *ProductServiceImpl) CreateProduct(ctx context.Context, req dto.ProductC
 	}
 	return dto.NewProductResponse(prod), nil
 }
+
+func (s *ProductServiceImpl) GetProduct(ctx context.Context, productID string) (res dto.ProductResponse, err error) {
+	prod, err := s.getProduct(ctx, productID, false)
+	if err != nil {
+		log.Error().Err(err).Msg("[GetProduct] Failed getProduct")
+		return
+	}
+	return dto.NewProductResponse(prod), nil
+}
+
+func (s *ProductServiceImpl) UpdateProduct(ctx context.Context, productID string, req dto.ProductUpdateRequest, userID uuid.UUID) (res dto.ProductResponse, err error) {
+	err = s.updateProduct(ctx, productID, userID, func(model.Product) dto.ProductUpdateRequest {
+		return req
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateProduct] Failed updateProduct")
+		return
+	}
+	return s.GetProduct(ctx, productID)
+}
+
+func (s *ProductServiceImpl) PatchProduct(ctx context.Context, productID string, req dto.ProductPatchRequest, userID uuid.UUID) (res dto.ProductResponse, err error) {
+	err = s.updateProduct(ctx, productID, userID, req.ToUpdateRequest)
+	if err != nil {
+		log.Error().Err(err).Msg("[PatchProduct] Failed updateProduct")
+		return
+	}
+	return s.GetProduct(ctx, productID)
+}
+
+// updateProduct applies the update built from the product to it with the
+// product locked, so stock taken or returned meanwhile by checkouts,
+// cancellations and refunds is not overwritten.
+func (s *ProductServiceImpl) updateProduct(ctx context.Context, productID string, userID uuid.UUID, update func(prod model.Product) dto.ProductUpdateRequest) (err error) {
+	if _, err = uuid.FromString(productID); err != nil {
+		return failure.BadRequest(err)
+	}
+	return s.DB.WithTransaction(func(tx *sqlx.Tx, e chan error) {
+		prod, err := s.Repo.GetProductByIDForUpdate(ctx, tx, productID)
+		if err == sql.ErrNoRows || (err == nil && prod.MetaDeletedAt.Valid) {
+			e <- failure.NotFound("product")
+			return
+		}
+		if err != nil {
+			e <- err
+			return
+		}
+		req := update(prod)
+		err = req.Apply(&prod, s.config.DefaultCurrency(), userID.String())
+		if err != nil {
+			e <- failure.BadRequest(err)
+			return
+		}
+		err = s.checkCategory(ctx, prod.CategoryID.String())
+		if err != nil {
+			e <- err
+			return
+		}
+		e <- s.Repo.UpdateProductTx(ctx, tx, &prod)
+	})
+}
+
+// DeleteProduct takes a product off the catalog. It stays referenced by
+// the orders and carts that hold it and can be restored.
+func (s *ProductServiceImpl) DeleteProduct(ctx context.Context, productID string, userID uuid.UUID) (err error) {
+	prod, err := s.getProduct(ctx, productID, false)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeleteProduct] Failed getProduct")
+		return
+	}
+	prod.DeletedBy = null.StringFrom(userID.String())
+	err = s.Repo.DeleteProduct(ctx, &prod)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeleteProduct] Failed DeleteProduct")
+		return
+	}
+	return nil
+}
+
+func (s *ProductServiceImpl) RestoreProduct(ctx context.Context, productID string, userID uuid.UUID) (res dto.ProductResponse, err error) {
+	prod, err := s.getProduct(ctx, productID, true)
+	if err != nil {
+		log.Error().Err(err).Msg("[RestoreProduct] Failed getProduct")
+		return
+	}
+	if !prod.MetaDeletedAt.Valid {
+		return res, failure.Conflict("restore", "product", "product is not deleted")
+	}
+	// its category may have been deleted while the product was
+	err = s.checkCategory(ctx, prod.CategoryID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[RestoreProduct] Failed checkCategory")
+		return
+	}
+	prod.UpdatedBy = userID.String()
+	err = s.Repo.RestoreProduct(ctx, &prod)
+	if err != nil {
+		log.Error().Err(err).Msg("[RestoreProduct] Failed RestoreProduct")
+		return
+	}
+	return s.GetProduct(ctx, productID)
+}
+
+// getProduct returns the product with productID, a deleted one only when
+// withDeleted is set.
+func (s *ProductServiceImpl) getProduct(ctx context.Context, productID string, withDeleted bool) (res model.Product, err error) {
+	if _, err = uuid.FromString(productID); err != nil {
+		return res, failure.BadRequest(err)
+	}
+	res, err = s.Repo.GetProductByID(ctx, productID)
+	if err == sql.ErrNoRows || (err == nil && res.MetaDeletedAt.Valid && !withDeleted) {
+		return model.Product{}, failure.NotFound("product")
+	}
+	return
+}
+
+// resolveCategory sets the categories the products of filter must be in.
+func (s *ProductServiceImpl) resolveCategory(ctx context.Context, filter *model.Filter) (err error) {
+	if filter.CategoryID == "" {
+		return nil
+	}
+	if !filter.IncludeDescendants {
+		if _, err = uuid.FromString(filter.CategoryID); err != nil {
+			return failure.BadRequest(err)
+		}
+		filter.CategoryIDs = []string{filter.CategoryID}
+		return nil
+	}
+	ids, err := s.CategorySvc.Descendants(ctx, filter.CategoryID)
+	if err != nil {
+		return
+	}
+	filter.CategoryIDs = make([]string, 0, len(ids))
+	for _, id := range ids {
+		filter.CategoryIDs = append(filter.CategoryIDs, id.String())
+	}
+	return nil
+}
+
+func (s *ProductServiceImpl) checkCategory(ctx context.Context, categoryID string) (err error) {
+	_, err = s.CategorySvc.GetCategory(ctx, categoryID)
+	if failure.GetCode(err) == http.StatusNotFound {
+		return failure.UnprocessableEntity("category not found")
+	}
+	return
+}
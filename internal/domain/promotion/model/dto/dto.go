@@ -0,0 +1,196 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/promotion/model"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+const maxCodeLength = 64
+
+// CreatePromotionRequest creates a promotion. Promotions without a code
+// apply automatically. PercentOff is in basis points, 1000 is 10%.
+type CreatePromotionRequest struct {
+	Code         string       `json:"code"`
+	Name         string       `json:"name"`
+	Type         string       `json:"type"`
+	PercentOff   int          `json:"percentOff"`
+	AmountOff    money.Amount `json:"amountOff"`
+	Currency     string       `json:"currency"`
+	BuyQuantity  int          `json:"buyQuantity"`
+	GetQuantity  int          `json:"getQuantity"`
+	CategoryID   string       `json:"categoryId"`
+	MinSubtotal  money.Amount `json:"minSubtotal"`
+	StartsAt     null.Time    `json:"startsAt"`
+	EndsAt       null.Time    `json:"endsAt"`
+	UsageLimit   null.Int     `json:"usageLimit"`
+	PerUserLimit null.Int     `json:"perUserLimit"`
+	Stackable    bool         `json:"stackable"`
+}
+
+type PromotionResponse struct {
+	ID            string         `json:"id"`
+	Code          null.String    `json:"code"`
+	Name          string         `json:"name"`
+	Type          string         `json:"type"`
+	PercentOff    int            `json:"percentOff"`
+	AmountOff     money.Amount   `json:"amountOff"`
+	Currency      money.Currency `json:"currency"`
+	BuyQuantity   int            `json:"buyQuantity"`
+	GetQuantity   int            `json:"getQuantity"`
+	CategoryID    uuid.NullUUID  `json:"categoryId"`
+	MinSubtotal   money.Amount   `json:"minSubtotal"`
+	StartsAt      null.Time      `json:"startsAt"`
+	EndsAt        null.Time      `json:"endsAt"`
+	UsageLimit    null.Int       `json:"usageLimit"`
+	PerUserLimit  null.Int       `json:"perUserLimit"`
+	Stackable     bool           `json:"stackable"`
+	CreatedBy     string         `json:"createdBy"`
+	MetaCreatedAt time.Time      `json:"metaCreatedAt"`
+}
+
+// NormalizeCode returns a coupon code the way it is stored.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (d *CreatePromotionRequest) ToModel(defaultCurrency money.Currency, by uuid.UUID) (res model.Promotion, err error) {
+	promotionType, err := model.ParsePromotionType(d.Type)
+	if err != nil {
+		return
+	}
+	if strings.TrimSpace(d.Name) == "" {
+		return res, errors.New("name must not be empty")
+	}
+
+	var code null.String
+	if normalized := NormalizeCode(d.Code); normalized != "" {
+		if len(normalized) > maxCodeLength {
+			return res, errors.New("code must be at most 64 characters")
+		}
+		code = null.StringFrom(normalized)
+	}
+
+	currency := defaultCurrency
+	if d.Currency != "" {
+		currency, err = money.ParseCurrency(d.Currency)
+		if err != nil {
+			return
+		}
+	}
+
+	var categoryID uuid.NullUUID
+	if d.CategoryID != "" {
+		id, err := uuid.FromString(d.CategoryID)
+		if err != nil {
+			return res, err
+		}
+		categoryID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	switch promotionType {
+	case model.PromotionPercentage:
+		if d.PercentOff <= 0 || d.PercentOff > 10000 {
+			return res, errors.New("percentOff must be between 1 and 10000 basis points")
+		}
+	case model.PromotionFixed:
+		if !d.AmountOff.IsPositive() {
+			return res, errors.New("amountOff must be greater than zero")
+		}
+	case model.PromotionBuyXGetY:
+		if d.BuyQuantity <= 0 || d.GetQuantity <= 0 {
+			return res, errors.New("buyQuantity and getQuantity must be greater than zero")
+		}
+	}
+	if d.MinSubtotal < 0 {
+		return res, errors.New("minSubtotal must not be negative")
+	}
+	if d.StartsAt.Valid && d.EndsAt.Valid && !d.EndsAt.Time.After(d.StartsAt.Time) {
+		return res, errors.New("endsAt must be after startsAt")
+	}
+	if (d.UsageLimit.Valid && d.UsageLimit.Int64 <= 0) || (d.PerUserLimit.Valid && d.PerUserLimit.Int64 <= 0) {
+		return res, errors.New("usage limits must be greater than zero")
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return
+	}
+	return model.Promotion{
+		ID:           id,
+		Code:         code,
+		Name:         strings.TrimSpace(d.Name),
+		Type:         int(promotionType),
+		PercentOff:   d.PercentOff,
+		AmountOff:    d.AmountOff,
+		Currency:     currency,
+		BuyQuantity:  d.BuyQuantity,
+		GetQuantity:  d.GetQuantity,
+		CategoryID:   categoryID,
+		MinSubtotal:  d.MinSubtotal,
+		StartsAt:     d.StartsAt,
+		EndsAt:       d.EndsAt,
+		UsageLimit:   d.UsageLimit,
+		PerUserLimit: d.PerUserLimit,
+		Stackable:    d.Stackable,
+		CreatedBy:    by,
+		UpdatedBy:    by,
+	}, nil
+}
+
+func NewPromotionResponse(promotion model.Promotion) PromotionResponse {
+	return PromotionResponse{
+		ID:            promotion.ID.String(),
+		Code:          promotion.Code,
+		Name:          promotion.Name,
+		Type:          model.PromotionType(promotion.Type).String(),
+		PercentOff:    promotion.PercentOff,
+		AmountOff:     promotion.AmountOff,
+		Currency:      promotion.Currency,
+		BuyQuantity:   promotion.BuyQuantity,
+		GetQuantity:   promotion.GetQuantity,
+		CategoryID:    promotion.CategoryID,
+		MinSubtotal:   promotion.MinSubtotal,
+		StartsAt:      promotion.StartsAt,
+		EndsAt:        promotion.EndsAt,
+		UsageLimit:    promotion.UsageLimit,
+		PerUserLimit:  promotion.PerUserLimit,
+		Stackable:     promotion.Stackable,
+		CreatedBy:     promotion.CreatedBy.String(),
+		MetaCreatedAt: promotion.MetaCreatedAt,
+	}
+}
+
+func NewPromotionListResponse(promotions []model.Promotion) []PromotionResponse {
+	res := make([]PromotionResponse, 0, len(promotions))
+	for _, promotion := range promotions {
+		res = append(res, NewPromotionResponse(promotion))
+	}
+	return res
+}
+
+// DiscountResponse is one promotion applied to a cart or an order.
+type DiscountResponse struct {
+	PromotionID  string       `json:"promotionId"`
+	Code         null.String  `json:"code"`
+	Name         string       `json:"name"`
+	Type         string       `json:"type"`
+	Amount       money.Amount `json:"amount"`
+	FreeShipping bool         `json:"freeShipping"`
+}
+
+func NewDiscountResponse(applied model.Applied) DiscountResponse {
+	return DiscountResponse{
+		PromotionID:  applied.PromotionID.String(),
+		Code:         applied.Code,
+		Name:         applied.Name,
+		Type:         model.PromotionType(applied.Type).String(),
+		Amount:       applied.Amount,
+		FreeShipping: applied.FreeShipping,
+	}
+}
@@ -0,0 +1,262 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+type PromotionType int
+
+var (
+	PromotionPercentage   PromotionType = 0
+	PromotionFixed        PromotionType = 1
+	PromotionFreeShipping PromotionType = 2
+	PromotionBuyXGetY     PromotionType = 3
+)
+
+var promotionTypeNames = map[PromotionType]string{
+	PromotionPercentage:   "percentage",
+	PromotionFixed:        "fixed",
+	PromotionFreeShipping: "free_shipping",
+	PromotionBuyXGetY:     "buy_x_get_y",
+}
+
+func (t PromotionType) String() string {
+	if name, ok := promotionTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", int(t))
+}
+
+// ParsePromotionType reads a type by its name, e.g. "free_shipping".
+func ParsePromotionType(s string) (PromotionType, error) {
+	for t, name := range promotionTypeNames {
+		if name == s {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown promotion type %q", s)
+}
+
+// Promotion is a discount rule. Promotions without a code apply to every
+// cart automatically, the others only once their code is applied.
+type Promotion struct {
+	ID   uuid.UUID   `db:"id"`
+	Code null.String `db:"code"`
+	Name string      `db:"name"`
+	Type int         `db:"type"`
+	// PercentOff is in basis points, 1000 is 10%.
+	PercentOff int            `db:"percent_off"`
+	AmountOff  money.Amount   `db:"amount_off"`
+	Currency   money.Currency `db:"currency"`
+	// BuyQuantity units bought get GetQuantity more units free.
+	BuyQuantity int `db:"buy_quantity"`
+	GetQuantity int `db:"get_quantity"`
+	// CategoryID limits the promotion to products of one category.
+	CategoryID    uuid.NullUUID `db:"category_id"`
+	MinSubtotal   money.Amount  `db:"min_subtotal"`
+	StartsAt      null.Time     `db:"starts_at"`
+	EndsAt        null.Time     `db:"ends_at"`
+	UsageLimit    null.Int      `db:"usage_limit"`
+	PerUserLimit  null.Int      `db:"per_user_limit"`
+	Stackable     bool          `db:"stackable"`
+	CreatedBy     uuid.UUID     `db:"created_by"`
+	MetaCreatedAt time.Time     `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID     `db:"updated_by"`
+	MetaUpdatedAt time.Time     `db:"meta_updated_at"`
+	DeletedBy     uuid.NullUUID `db:"deleted_by"`
+	MetaDeletedAt null.Time     `db:"meta_deleted_at"`
+}
+
+// IsActiveAt reports whether t falls in the validity window.
+func (m Promotion) IsActiveAt(t time.Time) bool {
+	if m.StartsAt.Valid && t.Before(m.StartsAt.Time) {
+		return false
+	}
+	if m.EndsAt.Valid && !t.Before(m.EndsAt.Time) {
+		return false
+	}
+	return true
+}
+
+// IsExhausted reports whether the global or the per-user usage cap is
+// reached, given how often the promotion was redeemed.
+func (m Promotion) IsExhausted(redeemed int, redeemedByUser int) bool {
+	if m.UsageLimit.Valid && int64(redeemed) >= m.UsageLimit.Int64 {
+		return true
+	}
+	if m.PerUserLimit.Valid && int64(redeemedByUser) >= m.PerUserLimit.Int64 {
+		return true
+	}
+	return false
+}
+
+// NeedsConversion reports whether the promotion holds amounts that must be
+// converted into the currency of the cart.
+func (m Promotion) NeedsConversion() bool {
+	return m.AmountOff != 0 || m.MinSubtotal != 0
+}
+
+// Line is a priced line of a cart the promotions are evaluated against.
+type Line struct {
+	ProductID  uuid.UUID
+	CategoryID uuid.UUID
+	Quantity   int
+	Total      money.Amount
+}
+
+func (m Promotion) inScope(line Line) bool {
+	return inCategory(m.CategoryID, line)
+}
+
+func inCategory(categoryID uuid.NullUUID, line Line) bool {
+	return !categoryID.Valid || categoryID.UUID == line.CategoryID
+}
+
+// Discount returns what the promotion takes off the lines, with the amounts
+// of the promotion converted by conversion. ok is false when the promotion
+// does not apply to the lines at all.
+func (m Promotion) Discount(lines []Line, conversion money.Conversion) (amount money.Amount, freeShipping bool, ok bool) {
+	var subtotal money.Amount
+	var scoped []Line
+	for _, line := range lines {
+		if m.inScope(line) {
+			scoped = append(scoped, line)
+			subtotal += line.Total
+		}
+	}
+	if len(scoped) == 0 || subtotal < conversion.Apply(m.MinSubtotal) {
+		return 0, false, false
+	}
+
+	switch PromotionType(m.Type) {
+	case PromotionPercentage:
+		amount = subtotal.Percent(m.PercentOff)
+	case PromotionFixed:
+		amount = conversion.Apply(m.AmountOff)
+	case PromotionFreeShipping:
+		freeShipping = true
+	case PromotionBuyXGetY:
+		group := m.BuyQuantity + m.GetQuantity
+		for _, line := range scoped {
+			if group <= 0 || line.Quantity < group {
+				continue
+			}
+			unitPrice := money.FromMinor(line.Total.Minor() / int64(line.Quantity))
+			amount += unitPrice.Mul(line.Quantity / group * m.GetQuantity)
+		}
+	}
+	if amount > subtotal {
+		amount = subtotal
+	}
+	return amount, freeShipping, amount.IsPositive() || freeShipping
+}
+
+// Applied is a promotion applied to a cart or an order.
+type Applied struct {
+	PromotionID  uuid.UUID
+	Code         null.String
+	Name         string
+	Type         int
+	Amount       money.Amount
+	FreeShipping bool
+	Stackable    bool
+	// CategoryID limits the lines the promotion took its amount off.
+	CategoryID uuid.NullUUID
+}
+
+// Allocate splits the amount of the promotion across the lines it applies
+// to, in proportion to their totals. Lines out of its scope get nothing.
+func (a Applied) Allocate(lines []Line) []money.Amount {
+	weights := make([]money.Amount, len(lines))
+	for i, line := range lines {
+		if inCategory(a.CategoryID, line) {
+			weights[i] = line.Total
+		}
+	}
+	return money.Allocate(a.Amount, weights)
+}
+
+// Evaluation is the outcome of evaluating the promotions of a cart.
+type Evaluation struct {
+	Applied       []Applied
+	DiscountTotal money.Amount
+	FreeShipping  bool
+}
+
+// Stack decides which of the candidates apply together. Stackable
+// promotions combine with each other, a non-stackable one only applies on
+// its own, so the customer gets whichever of the two saves more. The total
+// never exceeds subtotal.
+func Stack(candidates []Applied, subtotal money.Amount) (res Evaluation) {
+	var stacked Evaluation
+	var best Evaluation
+	for _, c := range candidates {
+		if c.Stackable {
+			stacked.add(c)
+			continue
+		}
+		single := Evaluation{}
+		single.add(c)
+		if single.beats(best) {
+			best = single
+		}
+	}
+	res = stacked
+	if best.beats(stacked) {
+		res = best
+	}
+
+	remaining := subtotal
+	for i := range res.Applied {
+		if res.Applied[i].Amount > remaining {
+			res.Applied[i].Amount = remaining
+		}
+		remaining -= res.Applied[i].Amount
+	}
+	res.DiscountTotal = subtotal - remaining
+	return
+}
+
+func (e *Evaluation) add(a Applied) {
+	e.Applied = append(e.Applied, a)
+	e.DiscountTotal += a.Amount
+	e.FreeShipping = e.FreeShipping || a.FreeShipping
+}
+
+func (e Evaluation) beats(other Evaluation) bool {
+	if e.DiscountTotal != other.DiscountTotal {
+		return e.DiscountTotal > other.DiscountTotal
+	}
+	return e.FreeShipping && !other.FreeShipping
+}
+
+// Redemption records that an order used a promotion, for the usage caps.
+type Redemption struct {
+	ID            uuid.UUID     `db:"id"`
+	PromotionID   uuid.UUID     `db:"promotion_id"`
+	UserID        uuid.UUID     `db:"user_id"`
+	OrderID       uuid.UUID     `db:"order_id"`
+	CreatedBy     uuid.UUID     `db:"created_by"`
+	MetaCreatedAt time.Time     `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID     `db:"updated_by"`
+	MetaUpdatedAt time.Time     `db:"meta_updated_at"`
+	DeletedBy     uuid.NullUUID `db:"deleted_by"`
+	MetaDeletedAt null.Time     `db:"meta_deleted_at"`
+}
+
+func NewRedemption(promotionID, userID, orderID uuid.UUID) Redemption {
+	id, _ := uuid.NewV4()
+	return Redemption{
+		ID:          id,
+		PromotionID: promotionID,
+		UserID:      userID,
+		OrderID:     orderID,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+	}
+}
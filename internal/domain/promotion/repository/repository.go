@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/infras"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/promotion/model"
+	"github.com/azka-zaydan/synapsis-test/shared/logger"
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type PromotionRepository interface {
+	CreatePromotion(ctx context.Context, promotion *model.Promotion) (err error)
+	GetPromotions(ctx context.Context) (res []model.Promotion, err error)
+	GetPromotionByCode(ctx context.Context, code string) (res model.Promotion, err error)
+	GetApplicablePromotions(ctx context.Context, promotionIds []uuid.UUID, at time.Time) (res []model.Promotion, err error)
+	GetApplicablePromotionsForUpdate(ctx context.Context, tx *sqlx.Tx, promotionIds []uuid.UUID, at time.Time) (res []model.Promotion, err error)
+	GetRedemptionCounts(ctx context.Context, promotionId string, userId string, releasedOrderStatuses []int) (redeemed int, redeemedByUser int, err error)
+	GetRedemptionCountsTx(ctx context.Context, tx *sqlx.Tx, promotionId string, userId string, releasedOrderStatuses []int) (redeemed int, redeemedByUser int, err error)
+	CreateRedemptionTx(ctx context.Context, tx *sqlx.Tx, redemption *model.Redemption) (err error)
+}
+
+type PromotionRepositoryMySQL struct {
+	DB *infras.MySQLConn
+}
+
+func ProvidePromotionRepositoryMySQL(db *infras.MySQLConn) *PromotionRepositoryMySQL {
+	return &PromotionRepositoryMySQL{
+		DB: db,
+	}
+}
+
+func (repo *PromotionRepositoryMySQL) CreatePromotion(ctx context.Context, promotion *model.Promotion) (err error) {
+	_, err = repo.DB.Write.NamedExecContext(ctx, promotionInsertQuery, promotion)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PromotionRepositoryMySQL) GetPromotions(ctx context.Context) (res []model.Promotion, err error) {
+	err = repo.DB.Read.SelectContext(ctx, &res, fmt.Sprintf("%s ORDER BY meta_created_at DESC, id", promotionSelectQuery))
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PromotionRepositoryMySQL) GetPromotionByCode(ctx context.Context, code string) (res model.Promotion, err error) {
+	err = repo.DB.Read.GetContext(ctx, &res, fmt.Sprintf("%s WHERE code = ?", promotionSelectQuery), code)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PromotionRepositoryMySQL) GetApplicablePromotions(ctx context.Context, promotionIds []uuid.UUID, at time.Time) (res []model.Promotion, err error) {
+	query, args, err := applicablePromotionsQuery(promotionIds, at, "")
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	err = repo.DB.Read.SelectContext(ctx, &res, query, args...)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *PromotionRepositoryMySQL) GetApplicablePromotionsForUpdate(ctx context.Context, tx *sqlx.Tx, promotionIds []uuid.UUID, at time.Time) (res []model.Promotion, err error) {
+	query, args, err := applicablePromotionsQuery(promotionIds, at, " FOR UPDATE")
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	err = tx.SelectContext(ctx, &res, query, args...)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+// applicablePromotionsQuery selects the automatic promotions and the given
+// ones that are in their validity window at at.
+func applicablePromotionsQuery(promotionIds []uuid.UUID, at time.Time, suffix string) (query string, args []interface{}, err error) {
+	scope := "code IS NULL"
+	args = []interface{}{}
+	if len(promotionIds) > 0 {
+		scope = "(code IS NULL OR id IN (?))"
+		args = append(args, promotionIds)
+	}
+	args = append(args, at, at)
+	query = fmt.Sprintf("%s WHERE %s AND (starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?) ORDER BY meta_created_at, id%s", promotionSelectQuery, scope, suffix)
+	return sqlx.In(query, args...)
+}
+
+func (repo *PromotionRepositoryMySQL) GetRedemptionCounts(ctx context.Context, promotionId string, userId string, releasedOrderStatuses []int) (redeemed int, redeemedByUser int, err error) {
+	query, args, err := sqlx.In(redemptionCountsQuery, userId, promotionId, releasedOrderStatuses)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	var counts redemptionCounts
+	err = repo.DB.Read.GetContext(ctx, &counts, query, args...)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return counts.Redeemed, counts.RedeemedByUser, nil
+}
+
+func (repo *PromotionRepositoryMySQL) GetRedemptionCountsTx(ctx context.Context, tx *sqlx.Tx, promotionId string, userId string, releasedOrderStatuses []int) (redeemed int, redeemedByUser int, err error) {
+	query, args, err := sqlx.In(redemptionCountsQuery, userId, promotionId, releasedOrderStatuses)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	var counts redemptionCounts
+	err = tx.GetContext(ctx, &counts, query, args...)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return counts.Redeemed, counts.RedeemedByUser, nil
+}
+
+type redemptionCounts struct {
+	Redeemed       int `db:"redeemed"`
+	RedeemedByUser int `db:"redeemed_by_user"`
+}
+
+func (repo *PromotionRepositoryMySQL) CreateRedemptionTx(ctx context.Context, tx *sqlx.Tx, redemption *model.Redemption) (err error) {
+	_, err = tx.NamedExecContext(ctx, redemptionInsertQuery, redemption)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+var (
+	promotionInsertQuery = `
+	INSERT INTO promotion (
+		id,
+		code,
+		name,
+		type,
+		percent_off,
+		amount_off,
+		currency,
+		buy_quantity,
+		get_quantity,
+		category_id,
+		min_subtotal,
+		starts_at,
+		ends_at,
+		usage_limit,
+		per_user_limit,
+		stackable,
+		created_by,
+		updated_by
+	) VALUES (
+		:id,
+		:code,
+		:name,
+		:type,
+		:percent_off,
+		:amount_off,
+		:currency,
+		:buy_quantity,
+		:get_quantity,
+		:category_id,
+		:min_subtotal,
+		:starts_at,
+		:ends_at,
+		:usage_limit,
+		:per_user_limit,
+		:stackable,
+		:created_by,
+		:updated_by
+	)`
+	promotionSelectQuery = `
+	SELECT
+		id,
+		code,
+		name,
+		type,
+		percent_off,
+		amount_off,
+		currency,
+		buy_quantity,
+		get_quantity,
+		category_id,
+		min_subtotal,
+		starts_at,
+		ends_at,
+		usage_limit,
+		per_user_limit,
+		stackable,
+		created_by,
+		meta_created_at,
+		updated_by,
+		meta_updated_at
+	FROM promotion`
+	// redemptions of orders that were cancelled or expired give the use back
+	redemptionCountsQuery = "SELECT COUNT(r.id) AS redeemed, COALESCE(SUM(r.user_id = ?), 0) AS redeemed_by_user FROM promotion_redemption r JOIN `order` o ON o.id = r.order_id WHERE r.promotion_id = ? AND o.status NOT IN (?)"
+	redemptionInsertQuery = `
+	INSERT INTO promotion_redemption (
+		id,
+		promotion_id,
+		user_id,
+		order_id,
+		created_by,
+		updated_by
+	) VALUES (
+		:id,
+		:promotion_id,
+		:user_id,
+		:order_id,
+		:created_by,
+		:updated_by
+	)`
+)
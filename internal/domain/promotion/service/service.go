@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/configs"
+	"github.com/azka-zaydan/synapsis-test/infras"
+	currencySvc "github.com/azka-zaydan/synapsis-test/internal/domain/currency/service"
+	orderModel "github.com/azka-zaydan/synapsis-test/internal/domain/order/model"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/promotion/model"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/promotion/model/dto"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/promotion/repository"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// releasedOrderStatuses are the statuses of orders whose promotion uses no
+// longer count towards the usage caps.
+var releasedOrderStatuses = []int{int(orderModel.OrderCancelledStatus), int(orderModel.OrderExpiredStatus)}
+
+// EvaluateRequest asks which promotions apply to the lines of a cart. The
+// automatic promotions are always considered, coupons only when listed in
+// PromotionIDs.
+type EvaluateRequest struct {
+	UserID       uuid.UUID
+	Currency     money.Currency
+	Lines        []model.Line
+	PromotionIDs []uuid.UUID
+}
+
+type PromotionService interface {
+	CreatePromotion(ctx context.Context, req dto.CreatePromotionRequest, adminID uuid.UUID) (res dto.PromotionResponse, err error)
+	ListPromotions(ctx context.Context) (res []dto.PromotionResponse, err error)
+	GetCoupon(ctx context.Context, code string, userID uuid.UUID) (res model.Promotion, err error)
+	Evaluate(ctx context.Context, req EvaluateRequest) (res model.Evaluation, err error)
+	RedeemTx(ctx context.Context, tx *sqlx.Tx, req EvaluateRequest, orderID uuid.UUID) (res model.Evaluation, err error)
+}
+
+type PromotionServiceImpl struct {
+	Repo        repository.PromotionRepository
+	DB          *infras.MySQLConn
+	config      *configs.Config
+	CurrencySvc currencySvc.CurrencyService
+}
+
+func ProvidePromotionServiceImpl(repo repository.PromotionRepository, db *infras.MySQLConn, config *configs.Config, currencySvc currencySvc.CurrencyService) *PromotionServiceImpl {
+	return &PromotionServiceImpl{
+		Repo:        repo,
+		DB:          db,
+		config:      config,
+		CurrencySvc: currencySvc,
+	}
+}
+
+func (s *PromotionServiceImpl) CreatePromotion(ctx context.Context, req dto.CreatePromotionRequest, adminID uuid.UUID) (res dto.PromotionResponse, err error) {
+	promotion, err := req.ToModel(s.config.DefaultCurrency(), adminID)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreatePromotion] Invalid Promotion")
+		return res, failure.UnprocessableEntity(err.Error())
+	}
+	if promotion.Code.Valid {
+		_, err = s.Repo.GetPromotionByCode(ctx, promotion.Code.String)
+		if err == nil {
+			return res, failure.Conflict("create", "promotion", "code is already in use")
+		}
+		if err != sql.ErrNoRows {
+			log.Error().Err(err).Msg("[CreatePromotion] Failed GetPromotionByCode")
+			return
+		}
+	}
+	err = s.Repo.CreatePromotion(ctx, &promotion)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreatePromotion] Failed CreatePromotion")
+		return
+	}
+	return dto.NewPromotionResponse(promotion), nil
+}
+
+func (s *PromotionServiceImpl) ListPromotions(ctx context.Context) (res []dto.PromotionResponse, err error) {
+	promotions, err := s.Repo.GetPromotions(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("[ListPromotions] Failed GetPromotions")
+		return
+	}
+	return dto.NewPromotionListResponse(promotions), nil
+}
+
+// GetCoupon returns the promotion of a coupon code the user may still use.
+func (s *PromotionServiceImpl) GetCoupon(ctx context.Context, code string, userID uuid.UUID) (res model.Promotion, err error) {
+	code = dto.NormalizeCode(code)
+	if code == "" {
+		return res, failure.BadRequestFromString("code must not be empty")
+	}
+	res, err = s.Repo.GetPromotionByCode(ctx, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = failure.NotFound("coupon")
+		}
+		log.Error().Err(err).Msg("[GetCoupon] Failed GetPromotionByCode")
+		return
+	}
+	if !res.IsActiveAt(time.Now()) {
+		return res, failure.UnprocessableEntity("coupon is not valid at this time")
+	}
+	redeemed, redeemedByUser, err := s.Repo.GetRedemptionCounts(ctx, res.ID.String(), userID.String(), releasedOrderStatuses)
+	if err != nil {
+		log.Error().Err(err).Msg("[GetCoupon] Failed GetRedemptionCounts")
+		return
+	}
+	if res.IsExhausted(redeemed, redeemedByUser) {
+		return res, failure.UnprocessableEntity("coupon has reached its usage limit")
+	}
+	return
+}
+
+// Evaluate returns the promotions that apply to the lines right now.
+func (s *PromotionServiceImpl) Evaluate(ctx context.Context, req EvaluateRequest) (res model.Evaluation, err error) {
+	if len(req.Lines) == 0 {
+		return
+	}
+	promotions, err := s.Repo.GetApplicablePromotions(ctx, req.PromotionIDs, time.Now())
+	if err != nil {
+		log.Error().Err(err).Msg("[Evaluate] Failed GetApplicablePromotions")
+		return
+	}
+	return s.evaluate(ctx, req, promotions, func(promotionID string) (int, int, error) {
+		return s.Repo.GetRedemptionCounts(ctx, promotionID, req.UserID.String(), releasedOrderStatuses)
+	})
+}
+
+// RedeemTx evaluates the promotions of an order being placed in tx and
+// records their use. The promotions stay locked until tx ends, so
+// concurrent checkouts cannot overrun a usage cap.
+func (s *PromotionServiceImpl) RedeemTx(ctx context.Context, tx *sqlx.Tx, req EvaluateRequest, orderID uuid.UUID) (res model.Evaluation, err error) {
+	if len(req.Lines) == 0 {
+		return
+	}
+	promotions, err := s.Repo.GetApplicablePromotionsForUpdate(ctx, tx, req.PromotionIDs, time.Now())
+	if err != nil {
+		log.Error().Err(err).Msg("[RedeemTx] Failed GetApplicablePromotionsForUpdate")
+		return
+	}
+	res, err = s.evaluate(ctx, req, promotions, func(promotionID string) (int, int, error) {
+		return s.Repo.GetRedemptionCountsTx(ctx, tx, promotionID, req.UserID.String(), releasedOrderStatuses)
+	})
+	if err != nil {
+		return
+	}
+	for _, applied := range res.Applied {
+		redemption := model.NewRedemption(applied.PromotionID, req.UserID, orderID)
+		err = s.Repo.CreateRedemptionTx(ctx, tx, &redemption)
+		if err != nil {
+			log.Error().Err(err).Msg("[RedeemTx] Failed CreateRedemptionTx")
+			return
+		}
+	}
+	return
+}
+
+func (s *PromotionServiceImpl) evaluate(ctx context.Context, req EvaluateRequest, promotions []model.Promotion, redemptionCounts func(promotionID string) (int, int, error)) (res model.Evaluation, err error) {
+	var subtotal money.Amount
+	for _, line := range req.Lines {
+		subtotal += line.Total
+	}
+
+	var candidates []model.Applied
+	for _, promotion := range promotions {
+		if promotion.UsageLimit.Valid || promotion.PerUserLimit.Valid {
+			redeemed, redeemedByUser, err := redemptionCounts(promotion.ID.String())
+			if err != nil {
+				log.Error().Err(err).Msg("[evaluate] Failed Counting Redemptions")
+				return res, err
+			}
+			if promotion.IsExhausted(redeemed, redeemedByUser) {
+				continue
+			}
+		}
+
+		conversion := money.Conversion{From: req.Currency, To: req.Currency}
+		if promotion.NeedsConversion() {
+			conversion, err = s.CurrencySvc.Conversion(ctx, promotion.Currency, req.Currency)
+			if err != nil {
+				// a promotion that cannot be priced in the cart currency does not apply
+				if _, ok := err.(*failure.Failure); ok {
+					log.Warn().Err(err).Str("promotion", promotion.ID.String()).Msg("[evaluate] Skipping Promotion")
+					err = nil
+					continue
+				}
+				log.Error().Err(err).Msg("[evaluate] Failed Conversion")
+				return
+			}
+		}
+
+		amount, freeShipping, ok := promotion.Discount(req.Lines, conversion)
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, model.Applied{
+			PromotionID:  promotion.ID,
+			Code:         promotion.Code,
+			Name:         promotion.Name,
+			Type:         promotion.Type,
+			Amount:       amount,
+			FreeShipping: freeShipping,
+			Stackable:    promotion.Stackable,
+			CategoryID:   promotion.CategoryID,
+		})
+	}
+	return model.Stack(candidates, subtotal), nil
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/reservation/model"
+	"github.com/gofrs/uuid"
+)
+
+// DefaultHoldTTL is used when no reservation TTL is configured.
+const DefaultHoldTTL = 15 * time.Minute
+
+type CreateReservationRequest struct {
+	OrderID   string
+	ProductID string
+	Quantity  int
+	TTL       time.Duration
+	CreatedBy string
+}
+
+func (d *CreateReservationRequest) ToModel() (res model.Reservation, err error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return
+	}
+	orderId, err := uuid.FromString(d.OrderID)
+	if err != nil {
+		return
+	}
+	productId, err := uuid.FromString(d.ProductID)
+	if err != nil {
+		return
+	}
+	createdBy, err := uuid.FromString(d.CreatedBy)
+	if err != nil {
+		return
+	}
+	ttl := d.TTL
+	if ttl <= 0 {
+		ttl = DefaultHoldTTL
+	}
+	return model.Reservation{
+		ID:        id,
+		OrderID:   orderId,
+		ProductID: productId,
+		Quantity:  d.Quantity,
+		Status:    int(model.ReservationHeld),
+		ExpiresAt: time.Now().Add(ttl),
+		CreatedBy: createdBy,
+		UpdatedBy: createdBy,
+	}, nil
+}
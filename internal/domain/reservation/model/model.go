@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+type ReservationStatus int
+
+var (
+	ReservationHeld      ReservationStatus = 0
+	ReservationConfirmed ReservationStatus = 1
+	ReservationReleased  ReservationStatus = 2
+)
+
+// Reservation is a time-limited hold on product stock placed at checkout.
+// The stock is already taken off the product while the hold is active; a
+// released hold gives it back.
+type Reservation struct {
+	ID            uuid.UUID     `db:"id"`
+	OrderID       uuid.UUID     `db:"order_id"`
+	ProductID     uuid.UUID     `db:"product_id"`
+	Quantity      int           `db:"quantity"`
+	Status        int           `db:"status"`
+	ExpiresAt     time.Time     `db:"expires_at"`
+	CreatedBy     uuid.UUID     `db:"created_by"`
+	MetaCreatedAt time.Time     `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID     `db:"updated_by"`
+	MetaUpdatedAt time.Time     `db:"meta_updated_at"`
+	DeletedBy     uuid.NullUUID `db:"deleted_by"`
+	MetaDeletedAt null.Time     `db:"meta_deleted_at"`
+}
+
+// IsExpired reports whether the hold is still pending but past its expiry.
+func (m *Reservation) IsExpired(now time.Time) bool {
+	return m.Status == int(ReservationHeld) && !now.Before(m.ExpiresAt)
+}
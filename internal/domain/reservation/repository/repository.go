@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/infras"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/reservation/model"
+	"github.com/azka-zaydan/synapsis-test/shared/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+type ReservationRepository interface {
+	CreateReservationTx(ctx context.Context, tx *sqlx.Tx, reservation *model.Reservation) (err error)
+	GetReservationsByOrderIDForUpdate(ctx context.Context, tx *sqlx.Tx, orderId string) (res []model.Reservation, err error)
+	GetExpiredReservationsForUpdate(ctx context.Context, tx *sqlx.Tx, now time.Time, limit int) (res []model.Reservation, err error)
+	UpdateReservationTx(ctx context.Context, tx *sqlx.Tx, reservation *model.Reservation) (err error)
+}
+
+type ReservationRepositoryMySQL struct {
+	DB *infras.MySQLConn
+}
+
+func ProvideReservationRepositoryMySQL(db *infras.MySQLConn) *ReservationRepositoryMySQL {
+	return &ReservationRepositoryMySQL{
+		DB: db,
+	}
+}
+
+func (repo *ReservationRepositoryMySQL) CreateReservationTx(ctx context.Context, tx *sqlx.Tx, reservation *model.Reservation) (err error) {
+	_, err = tx.NamedExecContext(ctx, reservationInsertQuery, reservation)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *ReservationRepositoryMySQL) GetReservationsByOrderIDForUpdate(ctx context.Context, tx *sqlx.Tx, orderId string) (res []model.Reservation, err error) {
+	err = tx.SelectContext(ctx, &res, fmt.Sprintf("%s WHERE order_id = ? FOR UPDATE", reservationSelectQuery), orderId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *ReservationRepositoryMySQL) GetExpiredReservationsForUpdate(ctx context.Context, tx *sqlx.Tx, now time.Time, limit int) (res []model.Reservation, err error) {
+	query := fmt.Sprintf("%s WHERE status = ? AND expires_at <= ? ORDER BY expires_at LIMIT %d FOR UPDATE SKIP LOCKED", reservationSelectQuery, limit)
+	err = tx.SelectContext(ctx, &res, query, model.ReservationHeld, now)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *ReservationRepositoryMySQL) UpdateReservationTx(ctx context.Context, tx *sqlx.Tx, reservation *model.Reservation) (err error) {
+	_, err = tx.NamedExecContext(ctx, reservationUpdateQuery, reservation)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+var (
+	reservationInsertQuery = `
+	INSERT INTO reservation (
+		id,
+		order_id,
+		product_id,
+		quantity,
+		status,
+		expires_at,
+		created_by,
+		updated_by
+	) VALUES (
+		:id,
+		:order_id,
+		:product_id,
+		:quantity,
+		:status,
+		:expires_at,
+		:created_by,
+		:updated_by
+	)`
+	reservationSelectQuery = `
+	SELECT
+		id,
+		order_id,
+		product_id,
+		quantity,
+		status,
+		expires_at,
+		created_by,
+		meta_created_at,
+		updated_by,
+		meta_updated_at
+	FROM reservation`
+	reservationUpdateQuery = `
+	UPDATE reservation SET
+		status = :status,
+		expires_at = :expires_at,
+		updated_by = :updated_by
+	WHERE id = :id
+`
+)
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/configs"
+	"github.com/azka-zaydan/synapsis-test/infras"
+	productRepo "github.com/azka-zaydan/synapsis-test/internal/domain/product/repository"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/reservation/model"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/reservation/repository"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// releaseBatchSize caps how many expired holds a single sweep releases.
+const releaseBatchSize = 100
+
+type ReservationService interface {
+	ReleaseExpired(ctx context.Context) (released int, err error)
+}
+
+type ReservationServiceImpl struct {
+	Repo        repository.ReservationRepository
+	DB          *infras.MySQLConn
+	config      *configs.Config
+	ProductRepo productRepo.ProductRepository
+}
+
+func ProvideReservationServiceImpl(repo repository.ReservationRepository, db *infras.MySQLConn, config *configs.Config, productRepo productRepo.ProductRepository) *ReservationServiceImpl {
+	return &ReservationServiceImpl{
+		Repo:        repo,
+		DB:          db,
+		config:      config,
+		ProductRepo: productRepo,
+	}
+}
+
+// ReleaseExpired returns the stock of expired, unconfirmed holds back to
+// their products.
+func (s *ReservationServiceImpl) ReleaseExpired(ctx context.Context) (released int, err error) {
+	err = s.DB.WithTransaction(func(tx *sqlx.Tx, e chan error) {
+		reservations, err := s.Repo.GetExpiredReservationsForUpdate(ctx, tx, time.Now(), releaseBatchSize)
+		if err != nil {
+			e <- err
+			return
+		}
+		for i := range reservations {
+			err = s.ProductRepo.AdjustProductStockTx(ctx, tx, reservations[i].ProductID.String(), reservations[i].Quantity)
+			if err != nil {
+				e <- err
+				return
+			}
+			reservations[i].Status = int(model.ReservationReleased)
+			err = s.Repo.UpdateReservationTx(ctx, tx, &reservations[i])
+			if err != nil {
+				e <- err
+				return
+			}
+		}
+		released = len(reservations)
+		e <- nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[ReleaseExpired] Failed Releasing Reservations")
+		return 0, err
+	}
+	return
+}
@@ -0,0 +1,138 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/shipping/model"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+// CreateShippingRateRequest adds a row to the shipping rate table. Weights
+// are in grams, MaxWeight is inclusive. Zone is a country code, leave it
+// empty to ship everywhere.
+type CreateShippingRateRequest struct {
+	Method    string       `json:"method"`
+	Name      string       `json:"name"`
+	Zone      string       `json:"zone"`
+	MinWeight int          `json:"minWeight"`
+	MaxWeight null.Int     `json:"maxWeight"`
+	Price     money.Amount `json:"price"`
+	Currency  string       `json:"currency"`
+}
+
+type ShippingRateResponse struct {
+	ID            string         `json:"id"`
+	Method        string         `json:"method"`
+	Name          string         `json:"name"`
+	Zone          null.String    `json:"zone"`
+	MinWeight     int            `json:"minWeight"`
+	MaxWeight     null.Int       `json:"maxWeight"`
+	Price         money.Amount   `json:"price"`
+	Currency      money.Currency `json:"currency"`
+	CreatedBy     string         `json:"createdBy"`
+	MetaCreatedAt time.Time      `json:"metaCreatedAt"`
+}
+
+type QuoteResponse struct {
+	Provider string         `json:"provider"`
+	Method   string         `json:"method"`
+	Name     string         `json:"name"`
+	Amount   money.Amount   `json:"amount"`
+	Currency money.Currency `json:"currency"`
+}
+
+// NormalizeMethod returns a shipping method the way it is stored.
+func NormalizeMethod(method string) string {
+	return strings.ToLower(strings.TrimSpace(method))
+}
+
+func (d *CreateShippingRateRequest) ToModel(defaultCurrency money.Currency, by uuid.UUID) (res model.ShippingRate, err error) {
+	method := NormalizeMethod(d.Method)
+	if method == "" {
+		return res, errors.New("method must not be empty")
+	}
+	if strings.TrimSpace(d.Name) == "" {
+		return res, errors.New("name must not be empty")
+	}
+	if d.MinWeight < 0 {
+		return res, errors.New("minWeight must not be negative")
+	}
+	if d.MaxWeight.Valid && d.MaxWeight.Int64 < int64(d.MinWeight) {
+		return res, errors.New("maxWeight must not be less than minWeight")
+	}
+	if d.Price < 0 {
+		return res, errors.New("price must not be negative")
+	}
+	currency := defaultCurrency
+	if d.Currency != "" {
+		currency, err = money.ParseCurrency(d.Currency)
+		if err != nil {
+			return
+		}
+	}
+	var zone null.String
+	if normalized := strings.ToUpper(strings.TrimSpace(d.Zone)); normalized != "" {
+		zone = null.StringFrom(normalized)
+	}
+	id, err := uuid.NewV4()
+	if err != nil {
+		return
+	}
+	return model.ShippingRate{
+		ID:        id,
+		Method:    method,
+		Name:      strings.TrimSpace(d.Name),
+		Zone:      zone,
+		MinWeight: d.MinWeight,
+		MaxWeight: d.MaxWeight,
+		Price:     d.Price,
+		Currency:  currency,
+		CreatedBy: by,
+		UpdatedBy: by,
+	}, nil
+}
+
+func NewShippingRateResponse(rate model.ShippingRate) ShippingRateResponse {
+	return ShippingRateResponse{
+		ID:            rate.ID.String(),
+		Method:        rate.Method,
+		Name:          rate.Name,
+		Zone:          rate.Zone,
+		MinWeight:     rate.MinWeight,
+		MaxWeight:     rate.MaxWeight,
+		Price:         rate.Price,
+		Currency:      rate.Currency,
+		CreatedBy:     rate.CreatedBy.String(),
+		MetaCreatedAt: rate.MetaCreatedAt,
+	}
+}
+
+func NewShippingRateListResponse(rates []model.ShippingRate) []ShippingRateResponse {
+	res := make([]ShippingRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		res = append(res, NewShippingRateResponse(rate))
+	}
+	return res
+}
+
+func NewQuoteResponse(quote model.Quote) QuoteResponse {
+	return QuoteResponse{
+		Provider: quote.Provider,
+		Method:   quote.Method,
+		Name:     quote.Name,
+		Amount:   quote.Amount,
+		Currency: quote.Currency,
+	}
+}
+
+func NewQuoteListResponse(quotes []model.Quote) []QuoteResponse {
+	res := make([]QuoteResponse, 0, len(quotes))
+	for _, quote := range quotes {
+		res = append(res, NewQuoteResponse(quote))
+	}
+	return res
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+// ShippingRate is a row of the shipping rate table. It prices parcels of
+// MinWeight up to MaxWeight grams shipped with Method into Zone. A rate
+// without a zone or a maximum weight has no such limit.
+type ShippingRate struct {
+	ID            uuid.UUID      `db:"id"`
+	Method        string         `db:"method"`
+	Name          string         `db:"name"`
+	Zone          null.String    `db:"zone"`
+	MinWeight     int            `db:"min_weight"`
+	MaxWeight     null.Int       `db:"max_weight"`
+	Price         money.Amount   `db:"price"`
+	Currency      money.Currency `db:"currency"`
+	CreatedBy     uuid.UUID      `db:"created_by"`
+	MetaCreatedAt time.Time      `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID      `db:"updated_by"`
+	MetaUpdatedAt time.Time      `db:"meta_updated_at"`
+	DeletedBy     uuid.NullUUID  `db:"deleted_by"`
+	MetaDeletedAt null.Time      `db:"meta_deleted_at"`
+}
+
+// Matches reports whether the rate prices a parcel of weight grams shipped
+// into zone.
+func (m ShippingRate) Matches(zone string, weight int) bool {
+	if m.Zone.Valid && m.Zone.String != zone {
+		return false
+	}
+	if weight < m.MinWeight {
+		return false
+	}
+	if m.MaxWeight.Valid && int64(weight) > m.MaxWeight.Int64 {
+		return false
+	}
+	return true
+}
+
+// Destination is where a parcel is shipped to. Country is an ISO 3166-1
+// alpha-2 code.
+type Destination struct {
+	Country    string
+	Region     string
+	City       string
+	PostalCode string
+}
+
+// QuoteRequest asks what shipping a parcel of Weight grams to Destination
+// costs.
+type QuoteRequest struct {
+	Destination Destination
+	Weight      int
+}
+
+// Quote is the price of shipping a parcel with one method. Conversion is
+// how Amount was converted from the currency the provider priced it in.
+type Quote struct {
+	Provider   string
+	Method     string
+	Name       string
+	Amount     money.Amount
+	Currency   money.Currency
+	Conversion money.Conversion
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/shipping/model"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/shipping/repository"
+)
+
+// ShippingRateProvider prices the shipping of a parcel, e.g. a carrier API.
+// Quotes are in the currency the provider charges in and their Method must
+// be unique across providers.
+type ShippingRateProvider interface {
+	Name() string
+	Quote(ctx context.Context, req model.QuoteRequest) (res []model.Quote, err error)
+}
+
+// Registry asks every registered provider for quotes.
+type Registry struct {
+	providers []ShippingRateProvider
+}
+
+// ProvideRegistry is the provider for Registry. Only the table rate
+// provider is shipped; carrier integrations register themselves here.
+func ProvideRegistry(repo repository.ShippingRepository) *Registry {
+	registry := &Registry{}
+	registry.Register(NewTableRateProvider(repo))
+	return registry
+}
+
+func (r *Registry) Register(p ShippingRateProvider) {
+	r.providers = append(r.providers, p)
+}
+
+// Quote returns the quotes of all providers, sorted by method.
+func (r *Registry) Quote(ctx context.Context, req model.QuoteRequest) (res []model.Quote, err error) {
+	for _, p := range r.providers {
+		quotes, err := p.Quote(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, quote := range quotes {
+			quote.Provider = p.Name()
+			res = append(res, quote)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Method < res[j].Method
+	})
+	return
+}
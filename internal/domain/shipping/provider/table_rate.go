@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/shipping/model"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/shipping/repository"
+)
+
+const TableRateProviderName = "table_rate"
+
+// TableRateProvider prices parcels from the shipping_rate table by weight
+// and zone, the zone being the destination country. For every method the
+// rate for the zone wins over a rate for all zones, and of two such rates
+// the newest.
+type TableRateProvider struct {
+	repo repository.ShippingRepository
+}
+
+func NewTableRateProvider(repo repository.ShippingRepository) *TableRateProvider {
+	return &TableRateProvider{
+		repo: repo,
+	}
+}
+
+func (p *TableRateProvider) Name() string {
+	return TableRateProviderName
+}
+
+func (p *TableRateProvider) Quote(ctx context.Context, req model.QuoteRequest) (res []model.Quote, err error) {
+	zone := req.Destination.Country
+	rates, err := p.repo.GetShippingRatesByZone(ctx, zone)
+	if err != nil {
+		return
+	}
+
+	best := make(map[string]model.ShippingRate)
+	var methods []string
+	for _, rate := range rates {
+		if !rate.Matches(zone, req.Weight) {
+			continue
+		}
+		current, found := best[rate.Method]
+		if !found {
+			methods = append(methods, rate.Method)
+		}
+		if !found || (rate.Zone.Valid && !current.Zone.Valid) {
+			best[rate.Method] = rate
+		}
+	}
+
+	for _, method := range methods {
+		rate := best[method]
+		res = append(res, model.Quote{
+			Method:   rate.Method,
+			Name:     rate.Name,
+			Amount:   rate.Price,
+			Currency: rate.Currency,
+		})
+	}
+	return
+}
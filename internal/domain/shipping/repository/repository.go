@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azka-zaydan/synapsis-test/infras"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/shipping/model"
+	"github.com/azka-zaydan/synapsis-test/shared/logger"
+)
+
+type ShippingRepository interface {
+	CreateShippingRate(ctx context.Context, rate *model.ShippingRate) (err error)
+	GetShippingRates(ctx context.Context) (res []model.ShippingRate, err error)
+	GetShippingRatesByZone(ctx context.Context, zone string) (res []model.ShippingRate, err error)
+}
+
+type ShippingRepositoryMySQL struct {
+	DB *infras.MySQLConn
+}
+
+func ProvideShippingRepositoryMySQL(db *infras.MySQLConn) *ShippingRepositoryMySQL {
+	return &ShippingRepositoryMySQL{
+		DB: db,
+	}
+}
+
+func (repo *ShippingRepositoryMySQL) CreateShippingRate(ctx context.Context, rate *model.ShippingRate) (err error) {
+	_, err = repo.DB.Write.NamedExecContext(ctx, shippingRateInsertQuery, rate)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *ShippingRepositoryMySQL) GetShippingRates(ctx context.Context) (res []model.ShippingRate, err error) {
+	err = repo.DB.Read.SelectContext(ctx, &res, fmt.Sprintf("%s ORDER BY method, meta_created_at DESC, id", shippingRateSelectQuery))
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *ShippingRepositoryMySQL) GetShippingRatesByZone(ctx context.Context, zone string) (res []model.ShippingRate, err error) {
+	query := fmt.Sprintf("%s WHERE zone = ? OR zone IS NULL ORDER BY meta_created_at DESC, id", shippingRateSelectQuery)
+	err = repo.DB.Read.SelectContext(ctx, &res, query, zone)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+var (
+	shippingRateInsertQuery = `
+	INSERT INTO shipping_rate (
+		id,
+		method,
+		name,
+		zone,
+		min_weight,
+		max_weight,
+		price,
+		currency,
+		created_by,
+		updated_by
+	) VALUES (
+		:id,
+		:method,
+		:name,
+		:zone,
+		:min_weight,
+		:max_weight,
+		:price,
+		:currency,
+		:created_by,
+		:updated_by
+	)`
+	shippingRateSelectQuery = `
+	SELECT
+		id,
+		method,
+		name,
+		zone,
+		min_weight,
+		max_weight,
+		price,
+		currency,
+		created_by,
+		meta_created_at,
+		updated_by,
+		meta_updated_at
+	FROM shipping_rate`
+)
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azka-zaydan/synapsis-test/configs"
+	currencySvc "github.com/azka-zaydan/synapsis-test/internal/domain/currency/service"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/shipping/model"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/shipping/model/dto"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/shipping/provider"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/shipping/repository"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type ShippingService interface {
+	CreateShippingRate(ctx context.Context, req dto.CreateShippingRateRequest, adminID uuid.UUID) (res dto.ShippingRateResponse, err error)
+	ListShippingRates(ctx context.Context) (res []dto.ShippingRateResponse, err error)
+	Quote(ctx context.Context, req model.QuoteRequest, currency money.Currency) (res []model.Quote, err error)
+	Rate(ctx context.Context, req model.QuoteRequest, currency money.Currency, method string) (res model.Quote, err error)
+}
+
+type ShippingServiceImpl struct {
+	Repo        repository.ShippingRepository
+	Providers   *provider.Registry
+	config      *configs.Config
+	CurrencySvc currencySvc.CurrencyService
+}
+
+func ProvideShippingServiceImpl(repo repository.ShippingRepository, providers *provider.Registry, config *configs.Config, currencySvc currencySvc.CurrencyService) *ShippingServiceImpl {
+	return &ShippingServiceImpl{
+		Repo:        repo,
+		Providers:   providers,
+		config:      config,
+		CurrencySvc: currencySvc,
+	}
+}
+
+func (s *ShippingServiceImpl) CreateShippingRate(ctx context.Context, req dto.CreateShippingRateRequest, adminID uuid.UUID) (res dto.ShippingRateResponse, err error) {
+	rate, err := req.ToModel(s.config.DefaultCurrency(), adminID)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateShippingRate] Invalid Shipping Rate")
+		return res, failure.UnprocessableEntity(err.Error())
+	}
+	err = s.Repo.CreateShippingRate(ctx, &rate)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateShippingRate] Failed CreateShippingRate")
+		return
+	}
+	return dto.NewShippingRateResponse(rate), nil
+}
+
+func (s *ShippingServiceImpl) ListShippingRates(ctx context.Context) (res []dto.ShippingRateResponse, err error) {
+	rates, err := s.Repo.GetShippingRates(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("[ListShippingRates] Failed GetShippingRates")
+		return
+	}
+	return dto.NewShippingRateListResponse(rates), nil
+}
+
+// Quote returns the shipping methods available for the parcel, priced in
+// currency.
+func (s *ShippingServiceImpl) Quote(ctx context.Context, req model.QuoteRequest, currency money.Currency) (res []model.Quote, err error) {
+	quotes, err := s.Providers.Quote(ctx, req)
+	if err != nil {
+		log.Error().Err(err).Msg("[Quote] Failed Quoting Providers")
+		return
+	}
+	for _, quote := range quotes {
+		conversion, err := s.CurrencySvc.Conversion(ctx, quote.Currency, currency)
+		if err != nil {
+			// a method that cannot be priced in the cart currency is not offered
+			if _, ok := err.(*failure.Failure); ok {
+				log.Warn().Err(err).Str("method", quote.Method).Msg("[Quote] Skipping Shipping Method")
+				continue
+			}
+			log.Error().Err(err).Msg("[Quote] Failed Conversion")
+			return nil, err
+		}
+		quote.Amount = conversion.Apply(quote.Amount)
+		quote.Currency = currency
+		quote.Conversion = conversion
+		res = append(res, quote)
+	}
+	return
+}
+
+// Rate returns the quote of one shipping method for the parcel, priced in
+// currency.
+func (s *ShippingServiceImpl) Rate(ctx context.Context, req model.QuoteRequest, currency money.Currency, method string) (res model.Quote, err error) {
+	method = dto.NormalizeMethod(method)
+	if method == "" {
+		return res, failure.BadRequestFromString("shipping method must not be empty")
+	}
+	quotes, err := s.Quote(ctx, req, currency)
+	if err != nil {
+		log.Error().Err(err).Msg("[Rate] Failed Quote")
+		return
+	}
+	for _, quote := range quotes {
+		if quote.Method == method {
+			return quote, nil
+		}
+	}
+	return res, failure.UnprocessableEntity(fmt.Sprintf("shipping method %s is not available for this address", method))
+}
@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/tax/model"
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+// CreateTaxRateRequest creates a tax rate. Rate is in basis points, 1100
+// is 11%. Leave CategoryID or Region empty to apply the rate to all.
+type CreateTaxRateRequest struct {
+	Name       string `json:"name"`
+	CategoryID string `json:"categoryId"`
+	Region     string `json:"region"`
+	Rate       int    `json:"rate"`
+	Inclusive  bool   `json:"inclusive"`
+}
+
+type TaxRateResponse struct {
+	ID            string        `json:"id"`
+	Name          string        `json:"name"`
+	CategoryID    uuid.NullUUID `json:"categoryId"`
+	Region        null.String   `json:"region"`
+	Rate          int           `json:"rate"`
+	Inclusive     bool          `json:"inclusive"`
+	CreatedBy     string        `json:"createdBy"`
+	MetaCreatedAt time.Time     `json:"metaCreatedAt"`
+}
+
+// NormalizeRegion returns a region code the way it is stored.
+func NormalizeRegion(region string) string {
+	return strings.ToUpper(strings.TrimSpace(region))
+}
+
+func (d *CreateTaxRateRequest) ToModel(by uuid.UUID) (res model.TaxRate, err error) {
+	if strings.TrimSpace(d.Name) == "" {
+		return res, errors.New("name must not be empty")
+	}
+	if d.Rate < 0 || d.Rate > 10000 {
+		return res, errors.New("rate must be between 0 and 10000 basis points")
+	}
+	var categoryID uuid.NullUUID
+	if d.CategoryID != "" {
+		id, err := uuid.FromString(d.CategoryID)
+		if err != nil {
+			return res, err
+		}
+		categoryID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+	var region null.String
+	if normalized := NormalizeRegion(d.Region); normalized != "" {
+		region = null.StringFrom(normalized)
+	}
+	id, err := uuid.NewV4()
+	if err != nil {
+		return
+	}
+	return model.TaxRate{
+		ID:         id,
+		Name:       strings.TrimSpace(d.Name),
+		CategoryID: categoryID,
+		Region:     region,
+		Rate:       d.Rate,
+		Inclusive:  d.Inclusive,
+		CreatedBy:  by,
+		UpdatedBy:  by,
+	}, nil
+}
+
+func NewTaxRateResponse(rate model.TaxRate) TaxRateResponse {
+	return TaxRateResponse{
+		ID:            rate.ID.String(),
+		Name:          rate.Name,
+		CategoryID:    rate.CategoryID,
+		Region:        rate.Region,
+		Rate:          rate.Rate,
+		Inclusive:     rate.Inclusive,
+		CreatedBy:     rate.CreatedBy.String(),
+		MetaCreatedAt: rate.MetaCreatedAt,
+	}
+}
+
+func NewTaxRateListResponse(rates []model.TaxRate) []TaxRateResponse {
+	res := make([]TaxRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		res = append(res, NewTaxRateResponse(rate))
+	}
+	return res
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/shared/money"
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+// TaxRate taxes the products of CategoryID sold in Region. A rate without
+// a category or a region applies to all of them. Inclusive rates are
+// already part of the price, exclusive ones are added on top of it.
+type TaxRate struct {
+	ID         uuid.UUID     `db:"id"`
+	Name       string        `db:"name"`
+	CategoryID uuid.NullUUID `db:"category_id"`
+	Region     null.String   `db:"region"`
+	// Rate is in basis points, 1100 is 11%.
+	Rate          int           `db:"rate"`
+	Inclusive     bool          `db:"inclusive"`
+	CreatedBy     uuid.UUID     `db:"created_by"`
+	MetaCreatedAt time.Time     `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID     `db:"updated_by"`
+	MetaUpdatedAt time.Time     `db:"meta_updated_at"`
+	DeletedBy     uuid.NullUUID `db:"deleted_by"`
+	MetaDeletedAt null.Time     `db:"meta_deleted_at"`
+}
+
+func (m TaxRate) matches(categoryID uuid.UUID, region string) bool {
+	if m.CategoryID.Valid && m.CategoryID.UUID != categoryID {
+		return false
+	}
+	if m.Region.Valid && m.Region.String != region {
+		return false
+	}
+	return true
+}
+
+// specificity ranks a rate for a category over one for a region, and
+// either over a catch-all rate.
+func (m TaxRate) specificity() int {
+	s := 0
+	if m.CategoryID.Valid {
+		s += 2
+	}
+	if m.Region.Valid {
+		s++
+	}
+	return s
+}
+
+// TaxOn returns the tax due on base, the amount a line is sold for.
+func (m TaxRate) TaxOn(base money.Amount) money.Amount {
+	if m.Inclusive {
+		return base.MulDiv(int64(m.Rate), int64(10000+m.Rate))
+	}
+	return base.Percent(m.Rate)
+}
+
+// Match returns the most specific of rates for a product of categoryID
+// sold in region. Of equally specific rates the first one wins, so rates
+// are expected newest first.
+func Match(rates []TaxRate, categoryID uuid.UUID, region string) (res TaxRate, ok bool) {
+	for _, rate := range rates {
+		if !rate.matches(categoryID, region) {
+			continue
+		}
+		if !ok || rate.specificity() > res.specificity() {
+			res, ok = rate, true
+		}
+	}
+	return
+}
+
+// Line is a line of an order to be taxed. Amount is what the line is sold
+// for after discounts.
+type Line struct {
+	CategoryID uuid.UUID
+	Amount     money.Amount
+}
+
+// LineTax is the tax of a Line. Lines no rate matches are untaxed.
+type LineTax struct {
+	Rate      int
+	Inclusive bool
+	Amount    money.Amount
+}
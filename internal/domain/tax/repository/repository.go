@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azka-zaydan/synapsis-test/infras"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/tax/model"
+	"github.com/azka-zaydan/synapsis-test/shared/logger"
+)
+
+type TaxRepository interface {
+	CreateTaxRate(ctx context.Context, rate *model.TaxRate) (err error)
+	GetTaxRates(ctx context.Context) (res []model.TaxRate, err error)
+	GetTaxRatesByRegion(ctx context.Context, region string) (res []model.TaxRate, err error)
+}
+
+type TaxRepositoryMySQL struct {
+	DB *infras.MySQLConn
+}
+
+func ProvideTaxRepositoryMySQL(db *infras.MySQLConn) *TaxRepositoryMySQL {
+	return &TaxRepositoryMySQL{
+		DB: db,
+	}
+}
+
+func (repo *TaxRepositoryMySQL) CreateTaxRate(ctx context.Context, rate *model.TaxRate) (err error) {
+	_, err = repo.DB.Write.NamedExecContext(ctx, taxRateInsertQuery, rate)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *TaxRepositoryMySQL) GetTaxRates(ctx context.Context) (res []model.TaxRate, err error) {
+	err = repo.DB.Read.SelectContext(ctx, &res, fmt.Sprintf("%s ORDER BY meta_created_at DESC, id", taxRateSelectQuery))
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *TaxRepositoryMySQL) GetTaxRatesByRegion(ctx context.Context, region string) (res []model.TaxRate, err error) {
+	query := fmt.Sprintf("%s WHERE region = ? OR region IS NULL ORDER BY meta_created_at DESC, id", taxRateSelectQuery)
+	err = repo.DB.Read.SelectContext(ctx, &res, query, region)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+var (
+	taxRateInsertQuery = `
+	INSERT INTO tax_rate (
+		id,
+		name,
+		category_id,
+		region,
+		rate,
+		inclusive,
+		created_by,
+		updated_by
+	) VALUES (
+		:id,
+		:name,
+		:category_id,
+		:region,
+		:rate,
+		:inclusive,
+		:created_by,
+		:updated_by
+	)`
+	taxRateSelectQuery = `
+	SELECT
+		id,
+		name,
+		category_id,
+		region,
+		rate,
+		inclusive,
+		created_by,
+		meta_created_at,
+		updated_by,
+		meta_updated_at
+	FROM tax_rate`
+)
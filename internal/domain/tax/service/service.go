@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/tax/model"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/tax/model/dto"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/tax/repository"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type TaxService interface {
+	CreateTaxRate(ctx context.Context, req dto.CreateTaxRateRequest, adminID uuid.UUID) (res dto.TaxRateResponse, err error)
+	ListTaxRates(ctx context.Context) (res []dto.TaxRateResponse, err error)
+	Calculate(ctx context.Context, region string, lines []model.Line) (res []model.LineTax, err error)
+}
+
+type TaxServiceImpl struct {
+	Repo repository.TaxRepository
+}
+
+func ProvideTaxServiceImpl(repo repository.TaxRepository) *TaxServiceImpl {
+	return &TaxServiceImpl{
+		Repo: repo,
+	}
+}
+
+func (s *TaxServiceImpl) CreateTaxRate(ctx context.Context, req dto.CreateTaxRateRequest, adminID uuid.UUID) (res dto.TaxRateResponse, err error) {
+	rate, err := req.ToModel(adminID)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateTaxRate] Invalid Tax Rate")
+		return res, failure.UnprocessableEntity(err.Error())
+	}
+	err = s.Repo.CreateTaxRate(ctx, &rate)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateTaxRate] Failed CreateTaxRate")
+		return
+	}
+	return dto.NewTaxRateResponse(rate), nil
+}
+
+func (s *TaxServiceImpl) ListTaxRates(ctx context.Context) (res []dto.TaxRateResponse, err error) {
+	rates, err := s.Repo.GetTaxRates(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("[ListTaxRates] Failed GetTaxRates")
+		return
+	}
+	return dto.NewTaxRateListResponse(rates), nil
+}
+
+// Calculate returns the tax of each line sold in region, in the order of
+// lines.
+func (s *TaxServiceImpl) Calculate(ctx context.Context, region string, lines []model.Line) (res []model.LineTax, err error) {
+	if len(lines) == 0 {
+		return
+	}
+	region = dto.NormalizeRegion(region)
+	rates, err := s.Repo.GetTaxRatesByRegion(ctx, region)
+	if err != nil {
+		log.Error().Err(err).Msg("[Calculate] Failed GetTaxRatesByRegion")
+		return
+	}
+	res = make([]model.LineTax, 0, len(lines))
+	for _, line := range lines {
+		rate, ok := model.Match(rates, line.CategoryID, region)
+		if !ok {
+			res = append(res, model.LineTax{})
+			continue
+		}
+		res = append(res, model.LineTax{
+			Rate:      rate.Rate,
+			Inclusive: rate.Inclusive,
+			Amount:    rate.TaxOn(line.Amount),
+		})
+	}
+	return
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/guregu/null"
+)
+
+// Address is an entry of a user's address book. Country is an ISO 3166-1
+// alpha-2 code and picks the shipping zone and the tax region.
+type Address struct {
+	ID            uuid.UUID     `db:"id"`
+	UserID        uuid.UUID     `db:"user_id"`
+	Label         string        `db:"label"`
+	RecipientName string        `db:"recipient_name"`
+	Phone         string        `db:"phone"`
+	Line1         string        `db:"line1"`
+	Line2         string        `db:"line2"`
+	City          string        `db:"city"`
+	Region        string        `db:"region"`
+	PostalCode    string        `db:"postal_code"`
+	Country       string        `db:"country"`
+	IsDefault     bool          `db:"is_default"`
+	CreatedBy     uuid.UUID     `db:"created_by"`
+	MetaCreatedAt time.Time     `db:"meta_created_at"`
+	UpdatedBy     uuid.UUID     `db:"updated_by"`
+	MetaUpdatedAt time.Time     `db:"meta_updated_at"`
+	DeletedBy     uuid.NullUUID `db:"deleted_by"`
+	MetaDeletedAt null.Time     `db:"meta_deleted_at"`
+}
@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/user/model"
+	"github.com/gofrs/uuid"
+)
+
+// AddressRequest creates or replaces an address. Country is an ISO 3166-1
+// alpha-2 code, e.g. "ID".
+type AddressRequest struct {
+	Label         string `json:"label"`
+	RecipientName string `json:"recipientName"`
+	Phone         string `json:"phone"`
+	Line1         string `json:"line1"`
+	Line2         string `json:"line2"`
+	City          string `json:"city"`
+	Region        string `json:"region"`
+	PostalCode    string `json:"postalCode"`
+	Country       string `json:"country"`
+	IsDefault     bool   `json:"isDefault"`
+}
+
+type AddressResponse struct {
+	ID            string    `json:"id"`
+	Label         string    `json:"label"`
+	RecipientName string    `json:"recipientName"`
+	Phone         string    `json:"phone"`
+	Line1         string    `json:"line1"`
+	Line2         string    `json:"line2"`
+	City          string    `json:"city"`
+	Region        string    `json:"region"`
+	PostalCode    string    `json:"postalCode"`
+	Country       string    `json:"country"`
+	IsDefault     bool      `json:"isDefault"`
+	MetaCreatedAt time.Time `json:"metaCreatedAt"`
+	MetaUpdatedAt time.Time `json:"metaUpdatedAt"`
+}
+
+// Validate reports why the request cannot be stored.
+func (d *AddressRequest) Validate() error {
+	if strings.TrimSpace(d.RecipientName) == "" {
+		return errors.New("recipientName must not be empty")
+	}
+	if strings.TrimSpace(d.Line1) == "" {
+		return errors.New("line1 must not be empty")
+	}
+	if strings.TrimSpace(d.City) == "" {
+		return errors.New("city must not be empty")
+	}
+	if len(strings.TrimSpace(d.Country)) != 2 {
+		return errors.New("country must be a two letter country code")
+	}
+	return nil
+}
+
+func (d *AddressRequest) ToModel(userID uuid.UUID) (res model.Address, err error) {
+	err = d.Validate()
+	if err != nil {
+		return
+	}
+	id, err := uuid.NewV4()
+	if err != nil {
+		return
+	}
+	res = model.Address{
+		ID:        id,
+		UserID:    userID,
+		CreatedBy: userID,
+	}
+	d.Apply(&res, userID)
+	return
+}
+
+// Apply replaces the fields of address with the request. The request must
+// be valid.
+func (d *AddressRequest) Apply(address *model.Address, by uuid.UUID) {
+	address.Label = strings.TrimSpace(d.Label)
+	address.RecipientName = strings.TrimSpace(d.RecipientName)
+	address.Phone = strings.TrimSpace(d.Phone)
+	address.Line1 = strings.TrimSpace(d.Line1)
+	address.Line2 = strings.TrimSpace(d.Line2)
+	address.City = strings.TrimSpace(d.City)
+	address.Region = strings.TrimSpace(d.Region)
+	address.PostalCode = strings.TrimSpace(d.PostalCode)
+	address.Country = strings.ToUpper(strings.TrimSpace(d.Country))
+	address.IsDefault = d.IsDefault
+	address.UpdatedBy = by
+}
+
+func NewAddressResponse(address model.Address) AddressResponse {
+	return AddressResponse{
+		ID:            address.ID.String(),
+		Label:         address.Label,
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+		Line1:         address.Line1,
+		Line2:         address.Line2,
+		City:          address.City,
+		Region:        address.Region,
+		PostalCode:    address.PostalCode,
+		Country:       address.Country,
+		IsDefault:     address.IsDefault,
+		MetaCreatedAt: address.MetaCreatedAt,
+		MetaUpdatedAt: address.MetaUpdatedAt,
+	}
+}
+
+func NewAddressListResponse(addresses []model.Address) []AddressResponse {
+	res := make([]AddressResponse, 0, len(addresses))
+	for _, address := range addresses {
+		res = append(res, NewAddressResponse(address))
+	}
+	return res
+}
@@ -17,6 +17,7 @@ type UserResponse struct {
 	ID            string      `json:"id"`
 	Username      string      `json:"username"`
 	Password      string      `json:"passwordHash"`
+	Role          string      `json:"role"`
 	MetaCreatedAt time.Time   `json:"metaCreatedAt"`
 	MetaUpdatedAt time.Time   `json:"metaUpdatedAt"`
 	MetaDeletedAt null.Time   `json:"metaDeletedAt"`
@@ -31,6 +32,7 @@ func NewUserResponse(user model.User) UserResponse {
 		ID:            user.ID.String(),
 		Username:      user.Username,
 		Password:      user.Password,
+		Role:          user.Role,
 		MetaCreatedAt: user.MetaCreatedAt,
 		MetaUpdatedAt: user.MetaUpdatedAt,
 		MetaDeletedAt: user.MetaDeletedAt,
@@ -48,6 +50,7 @@ func (d *CreateUserRequest) ToModel() model.User {
 		ID:        id,
 		Username:  d.Username,
 		Password:  d.Password,
+		Role:      model.RoleCustomer,
 		CreatedBy: id,
 		UpdatedBy: id,
 	}
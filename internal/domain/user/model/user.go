@@ -7,10 +7,17 @@ import (
 	"github.com/guregu/null"
 )
 
+const (
+	RoleCustomer = "customer"
+	// RoleAdmin may use the back office endpoints, e.g. refunds.
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID            uuid.UUID     `db:"id"`
 	Username      string        `db:"username"`
 	Password      string        `db:"password_hash"`
+	Role          string        `db:"role"`
 	MetaCreatedAt time.Time     `db:"meta_created_at"`
 	MetaUpdatedAt time.Time     `db:"meta_updated_at"`
 	MetaDeletedAt null.Time     `db:"meta_deleted_at"`
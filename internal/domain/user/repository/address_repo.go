@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/user/model"
+	"github.com/azka-zaydan/synapsis-test/shared/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+type AddressRepo interface {
+	CreateAddressTx(ctx context.Context, tx *sqlx.Tx, address *model.Address) (err error)
+	UpdateAddressTx(ctx context.Context, tx *sqlx.Tx, address *model.Address) (err error)
+	DeleteAddress(ctx context.Context, address *model.Address) (err error)
+	ClearDefaultAddressTx(ctx context.Context, tx *sqlx.Tx, userId string) (err error)
+	GetAddressesByUserID(ctx context.Context, userId string) (res []model.Address, err error)
+	GetAddressByID(ctx context.Context, addressId string, userId string) (res model.Address, err error)
+}
+
+func (repo *UserRepositoryMySQL) CreateAddressTx(ctx context.Context, tx *sqlx.Tx, address *model.Address) (err error) {
+	_, err = tx.NamedExecContext(ctx, addressInsertQuery, address)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *UserRepositoryMySQL) UpdateAddressTx(ctx context.Context, tx *sqlx.Tx, address *model.Address) (err error) {
+	_, err = tx.NamedExecContext(ctx, addressUpdateQuery, address)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *UserRepositoryMySQL) DeleteAddress(ctx context.Context, address *model.Address) (err error) {
+	_, err = repo.DB.Write.NamedExecContext(ctx, addressDeleteQuery, address)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *UserRepositoryMySQL) ClearDefaultAddressTx(ctx context.Context, tx *sqlx.Tx, userId string) (err error) {
+	_, err = tx.ExecContext(ctx, addressClearDefaultQuery, userId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *UserRepositoryMySQL) GetAddressesByUserID(ctx context.Context, userId string) (res []model.Address, err error) {
+	query := fmt.Sprintf("%s WHERE user_id = ? AND meta_deleted_at IS NULL ORDER BY is_default DESC, meta_created_at, id", addressSelectQuery)
+	err = repo.DB.Read.SelectContext(ctx, &res, query, userId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+func (repo *UserRepositoryMySQL) GetAddressByID(ctx context.Context, addressId string, userId string) (res model.Address, err error) {
+	query := fmt.Sprintf("%s WHERE id = ? AND user_id = ? AND meta_deleted_at IS NULL", addressSelectQuery)
+	err = repo.DB.Read.GetContext(ctx, &res, query, addressId, userId)
+	if err != nil {
+		logger.ErrorWithStack(err)
+		return
+	}
+	return
+}
+
+var (
+	addressInsertQuery = `
+	INSERT INTO address (
+		id,
+		user_id,
+		label,
+		recipient_name,
+		phone,
+		line1,
+		line2,
+		city,
+		region,
+		postal_code,
+		country,
+		is_default,
+		created_by,
+		updated_by
+	) VALUES (
+		:id,
+		:user_id,
+		:label,
+		:recipient_name,
+		:phone,
+		:line1,
+		:line2,
+		:city,
+		:region,
+		:postal_code,
+		:country,
+		:is_default,
+		:created_by,
+		:updated_by
+	)`
+	addressUpdateQuery = `
+	UPDATE address SET
+		label = :label,
+		recipient_name = :recipient_name,
+		phone = :phone,
+		line1 = :line1,
+		line2 = :line2,
+		city = :city,
+		region = :region,
+		postal_code = :postal_code,
+		country = :country,
+		is_default = :is_default,
+		updated_by = :updated_by
+	WHERE id = :id AND user_id = :user_id AND meta_deleted_at IS NULL`
+	addressDeleteQuery = `
+	UPDATE address SET
+		is_default = FALSE,
+		deleted_by = :deleted_by,
+		meta_deleted_at = CURRENT_TIMESTAMP
+	WHERE id = :id AND user_id = :user_id AND meta_deleted_at IS NULL`
+	addressClearDefaultQuery = `
+	UPDATE address SET
+		is_default = FALSE
+	WHERE user_id = ? AND is_default = TRUE`
+	addressSelectQuery = `
+	SELECT
+		id,
+		user_id,
+		label,
+		recipient_name,
+		phone,
+		line1,
+		line2,
+		city,
+		region,
+		postal_code,
+		country,
+		is_default,
+		created_by,
+		meta_created_at,
+		updated_by,
+		meta_updated_at,
+		deleted_by,
+		meta_deleted_at
+	FROM address`
+)
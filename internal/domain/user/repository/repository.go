@@ -6,6 +6,7 @@ import (
 
 type UserRepository interface {
 	UserRepo
+	AddressRepo
 }
 
 type UserRepositoryMySQL struct {
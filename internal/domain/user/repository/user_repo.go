@@ -30,7 +30,7 @@ func (repo *UserRepositoryMySQL) FindByUsername(ctx context.Context, username st
 		return
 	}
 
-	query := "SELECT id,username,password_hash,created_by,meta_created_at,updated_by,meta_updated_at FROM user WHERE username = ?"
+	query := "SELECT id,username,password_hash,role,created_by,meta_created_at,updated_by,meta_updated_at FROM user WHERE username = ?"
 
 	err = repo.DB.Read.GetContext(ctx, &res, query, username)
 	if err != nil {
@@ -64,6 +64,6 @@ func (repo *UserRepositoryMySQL) CreateUser(ctx context.Context, user *model.Use
 // queries
 var (
 	userInsertQuery = `
-	INSERT INTO user (id, username, password_hash, created_by, updated_by)
-	VALUES (:id, :username, :password_hash, :created_by, :updated_by)`
+	INSERT INTO user (id, username, password_hash, role, created_by, updated_by)
+	VALUES (:id, :username, :password_hash, :role, :created_by, :updated_by)`
 )
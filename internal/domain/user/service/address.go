@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/azka-zaydan/synapsis-test/internal/domain/user/model"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/user/model/dto"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+func (s *UserServiceImpl) ListAddresses(ctx context.Context, userID uuid.UUID) (res []dto.AddressResponse, err error) {
+	addresses, err := s.repo.GetAddressesByUserID(ctx, userID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[ListAddresses] Failed GetAddressesByUserID")
+		return
+	}
+	return dto.NewAddressListResponse(addresses), nil
+}
+
+func (s *UserServiceImpl) GetAddress(ctx context.Context, addressID string, userID uuid.UUID) (res dto.AddressResponse, err error) {
+	address, err := s.getAddress(ctx, addressID, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[GetAddress] Failed getAddress")
+		return
+	}
+	return dto.NewAddressResponse(address), nil
+}
+
+// CreateAddress adds an address to the address book. The first address of
+// a user becomes the default.
+func (s *UserServiceImpl) CreateAddress(ctx context.Context, req dto.AddressRequest, userID uuid.UUID) (res dto.AddressResponse, err error) {
+	address, err := req.ToModel(userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateAddress] Invalid Address")
+		return res, failure.UnprocessableEntity(err.Error())
+	}
+	existing, err := s.repo.GetAddressesByUserID(ctx, userID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateAddress] Failed GetAddressesByUserID")
+		return
+	}
+	if len(existing) == 0 {
+		address.IsDefault = true
+	}
+
+	err = s.db.WithTransaction(func(tx *sqlx.Tx, e chan error) {
+		if address.IsDefault {
+			err := s.repo.ClearDefaultAddressTx(ctx, tx, userID.String())
+			if err != nil {
+				e <- err
+				return
+			}
+		}
+		err := s.repo.CreateAddressTx(ctx, tx, &address)
+		if err != nil {
+			e <- err
+			return
+		}
+		e <- nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateAddress] Failed Create Transaction")
+		return
+	}
+	return dto.NewAddressResponse(address), nil
+}
+
+// UpdateAddress replaces an address. Orders placed earlier keep the copy of
+// the address they were placed with.
+func (s *UserServiceImpl) UpdateAddress(ctx context.Context, addressID string, req dto.AddressRequest, userID uuid.UUID) (res dto.AddressResponse, err error) {
+	err = req.Validate()
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateAddress] Invalid Address")
+		return res, failure.UnprocessableEntity(err.Error())
+	}
+	address, err := s.getAddress(ctx, addressID, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateAddress] Failed getAddress")
+		return
+	}
+	wasDefault := address.IsDefault
+	req.Apply(&address, userID)
+
+	err = s.db.WithTransaction(func(tx *sqlx.Tx, e chan error) {
+		if address.IsDefault && !wasDefault {
+			err := s.repo.ClearDefaultAddressTx(ctx, tx, userID.String())
+			if err != nil {
+				e <- err
+				return
+			}
+		}
+		err := s.repo.UpdateAddressTx(ctx, tx, &address)
+		if err != nil {
+			e <- err
+			return
+		}
+		e <- nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateAddress] Failed Update Transaction")
+		return
+	}
+	return dto.NewAddressResponse(address), nil
+}
+
+func (s *UserServiceImpl) DeleteAddress(ctx context.Context, addressID string, userID uuid.UUID) (err error) {
+	address, err := s.getAddress(ctx, addressID, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeleteAddress] Failed getAddress")
+		return
+	}
+	address.DeletedBy = uuid.NullUUID{UUID: userID, Valid: true}
+	err = s.repo.DeleteAddress(ctx, &address)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeleteAddress] Failed DeleteAddress")
+		return
+	}
+	return
+}
+
+func (s *UserServiceImpl) getAddress(ctx context.Context, addressID string, userID uuid.UUID) (res model.Address, err error) {
+	if _, err = uuid.FromString(addressID); err != nil {
+		return res, failure.BadRequest(err)
+	}
+	res, err = s.repo.GetAddressByID(ctx, addressID, userID.String())
+	if err == sql.ErrNoRows {
+		err = failure.NotFound("address")
+	}
+	return
+}
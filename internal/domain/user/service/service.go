@@ -3,25 +3,34 @@ package service
 import (
 	"context"
 
+	"github.com/azka-zaydan/synapsis-test/infras"
 	cartDto "github.com/azka-zaydan/synapsis-test/internal/domain/cart/model/dto"
 	cartSvc "github.com/azka-zaydan/synapsis-test/internal/domain/cart/service"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/user/model/dto"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/user/repository"
+	"github.com/gofrs/uuid"
 	"github.com/rs/zerolog/log"
 )
 
 type UserService interface {
 	CreateUser(ctx context.Context, req dto.CreateUserRequest) (res dto.UserResponse, err error)
+	ListAddresses(ctx context.Context, userID uuid.UUID) (res []dto.AddressResponse, err error)
+	GetAddress(ctx context.Context, addressID string, userID uuid.UUID) (res dto.AddressResponse, err error)
+	CreateAddress(ctx context.Context, req dto.AddressRequest, userID uuid.UUID) (res dto.AddressResponse, err error)
+	UpdateAddress(ctx context.Context, addressID string, req dto.AddressRequest, userID uuid.UUID) (res dto.AddressResponse, err error)
+	DeleteAddress(ctx context.Context, addressID string, userID uuid.UUID) (err error)
 }
 
 type UserServiceImpl struct {
-	repo    repository.UserRepo
+	repo    repository.UserRepository
+	db      *infras.MySQLConn
 	cartSvc cartSvc.CartService
 }
 
-func ProvideUserServiceImpl(repo repository.UserRepository, cartSvc cartSvc.CartService) *UserServiceImpl {
+func ProvideUserServiceImpl(repo repository.UserRepository, db *infras.MySQLConn, cartSvc cartSvc.CartService) *UserServiceImpl {
 	return &UserServiceImpl{
 		repo:    repo,
+		db:      db,
 		cartSvc: cartSvc,
 	}
 }
@@ -13,8 +13,9 @@ import (
 )
 
 type CartHandler struct {
-	auth    *middleware.Authentication
-	CartSvc service.CartService
+	auth        *middleware.Authentication
+	idempotency *middleware.Idempotency
+	CartSvc     service.CartService
 }
 
 func (h *CartHandler) Router(r fiber.Router) {
@@ -23,14 +24,19 @@ func (h *CartHandler) Router(r fiber.Router) {
 	cart.Post("/add-items", h.AddItems)
 	cart.Get("/list-items", h.ListItems)
 	cart.Post("/remove-items", h.DeleteItems)
+	cart.Post("/currency", h.SetCurrency)
+	cart.Post("/apply-coupon", h.ApplyCoupon)
+	cart.Get("/shipping-rates", h.ShippingRates)
 
-	cart.Post("/checkout", h.Checkout)
+	cart.Post("/checkout/preview", h.PreviewCheckout)
+	cart.Post("/checkout", h.idempotency.WithKey(), h.Checkout)
 }
 
-func ProvideCartHandler(svc service.CartService, auth *middleware.Authentication) CartHandler {
+func ProvideCartHandler(svc service.CartService, auth *middleware.Authentication, idempotency *middleware.Idempotency) CartHandler {
 	return CartHandler{
-		CartSvc: svc,
-		auth:    auth,
+		CartSvc:     svc,
+		auth:        auth,
+		idempotency: idempotency,
 	}
 }
 
@@ -40,7 +46,7 @@ func ProvideCartHandler(svc service.CartService, auth *middleware.Authentication
 // @Tags v1/cart
 // @Param Authorization header string true "Bearer Token"
 // @Produce json
-// @Success 201 {object} response.Base{}
+// @Success 200 {object} response.Base{data=dto.ListItemsResponse}
 // @Failure 400 {object} response.Base
 // @Failure 409 {object} response.Base
 // @Failure 500 {object} response.Base
@@ -135,16 +141,129 @@ func (h *CartHandler) DeleteItems(c *fiber.Ctx) error {
 	return response.WithJSON(c, fiber.StatusOK, res)
 }
 
+// SetCurrency switches the cart currency
+// @Summary switches the cart currency
+// @Description This endpoint switches the currency the cart is priced in and reprices its items with the rates in effect
+// @Tags v1/cart
+// @Param Authorization header string true "Bearer Token"
+// @Param setCurrencyRequest body dto.SetCurrencyRequest true "currency to price the cart in"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.ListItemsResponse}
+// @Failure 400 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/cart/currency [post]
+func (h *CartHandler) SetCurrency(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[SetCurrencyHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.SetCurrencyRequest
+	err = c.BodyParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[SetCurrencyHandler] Failed Parsing Body")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.CartSvc.SetCurrency(c.Context(), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[SetCurrencyHandler] Failed SetCurrency")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// ApplyCoupon applies a coupon code to the cart
+// @Summary applies a coupon code to the cart
+// @Description This endpoint attaches a coupon code to the cart and returns the cart with its discount breakdown
+// @Tags v1/cart
+// @Param Authorization header string true "Bearer Token"
+// @Param applyCouponRequest body dto.ApplyCouponRequest true "coupon code to apply"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.ListItemsResponse}
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/cart/apply-coupon [post]
+func (h *CartHandler) ApplyCoupon(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[ApplyCouponHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.ApplyCouponRequest
+	err = c.BodyParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[ApplyCouponHandler] Failed Parsing Body")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.CartSvc.ApplyCoupon(c.Context(), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[ApplyCouponHandler] Failed ApplyCoupon")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// ShippingRates lists the shipping methods for the cart
+// @Summary lists the shipping methods for the cart
+// @Description This endpoint lists the shipping methods the cart can be shipped with to an address of the user, priced in the cart currency
+// @Tags v1/cart
+// @Param Authorization header string true "Bearer Token"
+// @Param addressId query string false "address id, the default address when empty"
+// @Produce json
+// @Success 200 {object} response.Base{data=[]dto.ShippingQuoteResponse}
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/cart/shipping-rates [get]
+func (h *CartHandler) ShippingRates(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[ShippingRatesHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.ShippingRatesRequest
+	err = c.QueryParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[ShippingRatesHandler] Failed Parsing Query")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.CartSvc.ShippingRates(c.Context(), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[ShippingRatesHandler] Failed ShippingRates")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
 // Checkout checks out items based on request
 // @Summary checks out items based on request
-// @Description This endpoint checks out items based on request
+// @Description This endpoint checks out items based on request and ships them with the chosen shipping method to an address of the user. The response reports every requested item as accepted, insufficient_stock, quantity_mismatch, not_in_cart, duplicate or unavailable, when the product was deleted. Rejected items stay in the cart; in strict mode any rejected item fails the whole checkout.
 // @Tags v1/cart
 // @Param Authorization header string true "Bearer Token"
-// @Param addItemsRequest body dto.CheckoutRequest true "items to be deleted"
+// @Param Idempotency-Key header string false "key to safely retry the request"
+// @Param checkoutRequest body dto.CheckoutRequest true "items to check out and where to ship them"
 // @Produce json
-// @Success 201 {object} response.Base{}
+// @Success 200 {object} response.Base{data=dto.CheckoutResponse}
 // @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
 // @Failure 409 {object} response.Base
+// @Failure 422 {object} response.Base
 // @Failure 500 {object} response.Base
 // @Router /v1/cart/checkout [post]
 func (h *CartHandler) Checkout(c *fiber.Ctx) error {
@@ -170,3 +289,40 @@ func (h *CartHandler) Checkout(c *fiber.Ctx) error {
 
 	return response.WithJSON(c, fiber.StatusOK, res)
 }
+
+// PreviewCheckout prices a checkout without placing it
+// @Summary prices a checkout without placing it
+// @Description This endpoint selects, prices and checks the stock of the requested items the way checkout does, without writing anything. The response has the priced items, the totals breakdown and the status of every requested item. Pass its quoteToken to checkout to reject the checkout with 409 if its items or totals no longer match the preview. No token is issued when the checkout would be rejected.
+// @Tags v1/cart
+// @Param Authorization header string true "Bearer Token"
+// @Param checkoutRequest body dto.CheckoutRequest true "items to check out and where to ship them"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.CheckoutPreviewResponse}
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/cart/checkout/preview [post]
+func (h *CartHandler) PreviewCheckout(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[PreviewCheckoutHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.CheckoutRequest
+	err = c.BodyParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[PreviewCheckoutHandler] Failed Parsing Body")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.CartSvc.PreviewCheckout(c.Context(), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[PreviewCheckoutHandler] Failed PreviewCheckout")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
@@ -0,0 +1,189 @@
+package category
+
+import (
+	"github.com/azka-zaydan/synapsis-test/internal/domain/category/model/dto"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/category/service"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/jwt"
+	"github.com/azka-zaydan/synapsis-test/transport/http/middleware"
+	"github.com/azka-zaydan/synapsis-test/transport/http/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type CategoryHandler struct {
+	CategorySvc service.CategoryService
+	auth        *middleware.Authentication
+}
+
+func (h *CategoryHandler) Router(r fiber.Router) {
+	category := r.Group("/category", h.auth.JWTAuth())
+
+	category.Get("/", h.ListCategories)
+	category.Get("/tree", h.GetCategoryTree)
+	category.Get("/:id", h.GetCategory)
+	category.Post("/", h.auth.AdminOnly(), h.CreateCategory)
+	category.Put("/:id", h.auth.AdminOnly(), h.UpdateCategory)
+	category.Delete("/:id", h.auth.AdminOnly(), h.DeleteCategory)
+}
+
+func ProvideCategoryHandler(svc service.CategoryService, auth *middleware.Authentication) CategoryHandler {
+	return CategoryHandler{
+		CategorySvc: svc,
+		auth:        auth,
+	}
+}
+
+// ListCategories lists all categories
+// @Summary lists all categories
+// @Description This endpoint lists all categories by name, each with the id of its parent
+// @Tags v1/category
+// @Param Authorization header string true "Bearer Token"
+// @Produce json
+// @Success 200 {object} response.Base{data=[]dto.CategoryResponse}
+// @Failure 500 {object} response.Base
+// @Router /v1/category/ [get]
+func (h *CategoryHandler) ListCategories(c *fiber.Ctx) error {
+	res, err := h.CategorySvc.ListCategories(c.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("[ListCategoriesHandler] Failed ListCategories")
+		return response.WithError(c, err)
+	}
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// GetCategoryTree gets the category tree
+// @Summary gets the category tree
+// @Description This endpoint gets the top level categories with the categories below them nested as children
+// @Tags v1/category
+// @Param Authorization header string true "Bearer Token"
+// @Produce json
+// @Success 200 {object} response.Base{data=[]dto.CategoryTreeResponse}
+// @Failure 500 {object} response.Base
+// @Router /v1/category/tree [get]
+func (h *CategoryHandler) GetCategoryTree(c *fiber.Ctx) error {
+	res, err := h.CategorySvc.GetCategoryTree(c.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("[GetCategoryTreeHandler] Failed GetCategoryTree")
+		return response.WithError(c, err)
+	}
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// GetCategory gets a category
+// @Summary gets a category
+// @Description This endpoint gets a category
+// @Tags v1/category
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "category id"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.CategoryResponse}
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/category/{id} [get]
+func (h *CategoryHandler) GetCategory(c *fiber.Ctx) error {
+	res, err := h.CategorySvc.GetCategory(c.Context(), c.Params("id"))
+	if err != nil {
+		log.Error().Err(err).Msg("[GetCategoryHandler] Failed GetCategory")
+		return response.WithError(c, err)
+	}
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// CreateCategory creates a category
+// @Summary creates a category
+// @Description This endpoint creates a category, below parentId when it is set. Admin only.
+// @Tags v1/category
+// @Param Authorization header string true "Bearer Token"
+// @Param categoryRequest body dto.CategoryRequest true "category to create"
+// @Produce json
+// @Success 201 {object} response.Base{data=dto.CategoryResponse}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/category/ [post]
+func (h *CategoryHandler) CreateCategory(c *fiber.Ctx) error {
+	userID, err := uuid.FromString(jwt.GetClaims(c)["userID"].(string))
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateCategoryHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.CategoryRequest
+	err = c.BodyParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateCategoryHandler] Failed Parsing Body")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	res, err := h.CategorySvc.CreateCategory(c.Context(), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateCategoryHandler] Failed CreateCategory")
+		return response.WithError(c, err)
+	}
+	return response.WithJSON(c, fiber.StatusCreated, res)
+}
+
+// UpdateCategory replaces a category
+// @Summary replaces a category
+// @Description This endpoint renames a category or moves it, with the categories below it, under another parent. Admin only.
+// @Tags v1/category
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "category id"
+// @Param categoryRequest body dto.CategoryRequest true "new category"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.CategoryResponse}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/category/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *fiber.Ctx) error {
+	userID, err := uuid.FromString(jwt.GetClaims(c)["userID"].(string))
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateCategoryHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.CategoryRequest
+	err = c.BodyParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateCategoryHandler] Failed Parsing Body")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	res, err := h.CategorySvc.UpdateCategory(c.Context(), c.Params("id"), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateCategoryHandler] Failed UpdateCategory")
+		return response.WithError(c, err)
+	}
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// DeleteCategory deletes a category
+// @Summary deletes a category
+// @Description This endpoint deletes a category. Categories that still have subcategories or products cannot be deleted. Admin only.
+// @Tags v1/category
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "category id"
+// @Produce json
+// @Success 200 {object} response.Base{}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/category/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *fiber.Ctx) error {
+	userID, err := uuid.FromString(jwt.GetClaims(c)["userID"].(string))
+	if err != nil {
+		log.Error().Err(err).Msg("[DeleteCategoryHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	err = h.CategorySvc.DeleteCategory(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeleteCategoryHandler] Failed DeleteCategory")
+		return response.WithError(c, err)
+	}
+	return response.WithMessage(c, fiber.StatusOK, "category deleted")
+}
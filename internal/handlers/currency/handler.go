@@ -0,0 +1,88 @@
+package currency
+
+import (
+	"github.com/azka-zaydan/synapsis-test/internal/domain/currency/model/dto"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/currency/service"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/jwt"
+	"github.com/azka-zaydan/synapsis-test/transport/http/middleware"
+	"github.com/azka-zaydan/synapsis-test/transport/http/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type CurrencyHandler struct {
+	CurrencySvc service.CurrencyService
+	auth        *middleware.Authentication
+}
+
+func (h *CurrencyHandler) Router(r fiber.Router) {
+	currency := r.Group("/currency", h.auth.JWTAuth())
+
+	currency.Get("/rates", h.ListRates)
+	currency.Post("/rates", h.auth.AdminOnly(), h.LoadRates)
+}
+
+func ProvideCurrencyHandler(svc service.CurrencyService, auth *middleware.Authentication) CurrencyHandler {
+	return CurrencyHandler{
+		CurrencySvc: svc,
+		auth:        auth,
+	}
+}
+
+// ListRates lists the exchange rates in effect
+// @Summary lists the exchange rates in effect
+// @Description This endpoint lists the exchange rate in effect for every currency pair
+// @Tags v1/currency
+// @Param Authorization header string true "Bearer Token"
+// @Produce json
+// @Success 200 {object} response.Base{data=[]dto.ExchangeRateResponse}
+// @Failure 500 {object} response.Base
+// @Router /v1/currency/rates [get]
+func (h *CurrencyHandler) ListRates(c *fiber.Ctx) error {
+	res, err := h.CurrencySvc.ListRates(c.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("[ListRatesHandler] Failed ListRates")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// LoadRates loads exchange rates
+// @Summary loads exchange rates
+// @Description This endpoint loads a batch of exchange rates taking effect at effectiveAt, or now. Admin only.
+// @Tags v1/currency
+// @Param Authorization header string true "Bearer Token"
+// @Param loadRatesRequest body dto.LoadRatesRequest true "rates to load"
+// @Produce json
+// @Success 201 {object} response.Base{data=[]dto.ExchangeRateResponse}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/currency/rates [post]
+func (h *CurrencyHandler) LoadRates(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[LoadRatesHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.LoadRatesRequest
+	err = c.BodyParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[LoadRatesHandler] Failed Parsing Body")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.CurrencySvc.LoadRates(c.Context(), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[LoadRatesHandler] Failed LoadRates")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusCreated, res)
+}
@@ -0,0 +1,212 @@
+package order
+
+import (
+	"github.com/azka-zaydan/synapsis-test/internal/domain/order/model/dto"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/order/service"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/jwt"
+	"github.com/azka-zaydan/synapsis-test/transport/http/middleware"
+	"github.com/azka-zaydan/synapsis-test/transport/http/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type OrderHandler struct {
+	auth     *middleware.Authentication
+	OrderSvc service.OrderService
+}
+
+func (h *OrderHandler) Router(r fiber.Router) {
+	order := r.Group("/order", h.auth.JWTAuth())
+
+	order.Get("/", h.ListOrders)
+	order.Get("/:id", h.GetOrder)
+	order.Post("/:id/cancel", h.CancelOrder)
+	order.Post("/:id/ship", h.auth.AdminOnly(), h.ShipOrder)
+	order.Post("/:id/deliver", h.auth.AdminOnly(), h.DeliverOrder)
+}
+
+func ProvideOrderHandler(svc service.OrderService, auth *middleware.Authentication) OrderHandler {
+	return OrderHandler{
+		OrderSvc: svc,
+		auth:     auth,
+	}
+}
+
+// ListOrders gets the order history of the user
+// @Summary gets the order history of the user
+// @Description This endpoint gets the orders of the logged in user, newest first
+// @Tags v1/order
+// @Param Authorization header string true "Bearer Token"
+// @Param page query int false "page number"
+// @Param pageSize query int false "page size"
+// @Produce json
+// @Success 200 {object} response.Base{data=[]dto.OrderResponse}
+// @Failure 400 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/order/ [get]
+func (h *OrderHandler) ListOrders(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[ListOrdersHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.ListOrdersRequest
+	err = c.QueryParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[ListOrdersHandler] Failed Parsing Query")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.OrderSvc.ListOrders(c.Context(), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[ListOrdersHandler] Failed ListOrders")
+		return response.WithError(c, err)
+	}
+
+	return response.WithMetadata(c, fiber.StatusOK, res.Data, res.Metadata)
+}
+
+// GetOrder gets an order with its details
+// @Summary gets an order with its details
+// @Description This endpoint gets an order of the logged in user with its items and status history
+// @Tags v1/order
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "order id"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.OrderWithDetailsResponse}
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/order/{id} [get]
+func (h *OrderHandler) GetOrder(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[GetOrderHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	orderID, err := uuid.FromString(c.Params("id"))
+	if err != nil {
+		log.Error().Err(err).Msg("[GetOrderHandler] Failed Parsing Order ID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.OrderSvc.GetOrder(c.Context(), orderID.String(), userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[GetOrderHandler] Failed GetOrder")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// CancelOrder cancels an order
+// @Summary cancels an order
+// @Description This endpoint cancels an order that is unpaid or paid but not yet shipped, and restocks its items
+// @Tags v1/order
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "order id"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.OrderResponse}
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/order/{id}/cancel [post]
+func (h *OrderHandler) CancelOrder(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[CancelOrderHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	orderID, err := uuid.FromString(c.Params("id"))
+	if err != nil {
+		log.Error().Err(err).Msg("[CancelOrderHandler] Failed Parsing Order ID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.OrderSvc.CancelOrder(c.Context(), orderID.String(), userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[CancelOrderHandler] Failed CancelOrder")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// ShipOrder marks an order as shipped
+// @Summary marks an order as shipped
+// @Description This endpoint marks a paid order as shipped. Shipped orders can no longer be cancelled. Admin only.
+// @Tags v1/order
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "order id"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.OrderResponse}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/order/{id}/ship [post]
+func (h *OrderHandler) ShipOrder(c *fiber.Ctx) error {
+	adminID, err := uuid.FromString(jwt.GetClaims(c)["userID"].(string))
+	if err != nil {
+		log.Error().Err(err).Msg("[ShipOrderHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	orderID, err := uuid.FromString(c.Params("id"))
+	if err != nil {
+		log.Error().Err(err).Msg("[ShipOrderHandler] Failed Parsing Order ID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.OrderSvc.ShipOrder(c.Context(), orderID.String(), adminID)
+	if err != nil {
+		log.Error().Err(err).Msg("[ShipOrderHandler] Failed ShipOrder")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// DeliverOrder marks an order as delivered
+// @Summary marks an order as delivered
+// @Description This endpoint marks a shipped order as delivered, completing it. Admin only.
+// @Tags v1/order
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "order id"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.OrderResponse}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/order/{id}/deliver [post]
+func (h *OrderHandler) DeliverOrder(c *fiber.Ctx) error {
+	adminID, err := uuid.FromString(jwt.GetClaims(c)["userID"].(string))
+	if err != nil {
+		log.Error().Err(err).Msg("[DeliverOrderHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	orderID, err := uuid.FromString(c.Params("id"))
+	if err != nil {
+		log.Error().Err(err).Msg("[DeliverOrderHandler] Failed Parsing Order ID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.OrderSvc.DeliverOrder(c.Context(), orderID.String(), adminID)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeliverOrderHandler] Failed DeliverOrder")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
@@ -4,27 +4,42 @@ import (
 	"github.com/azka-zaydan/synapsis-test/internal/domain/payment/model/dto"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/payment/service"
 	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/jwt"
 	"github.com/azka-zaydan/synapsis-test/transport/http/middleware"
 	"github.com/azka-zaydan/synapsis-test/transport/http/response"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid"
 	"github.com/rs/zerolog/log"
 )
 
+const (
+	webhookSignatureHeader = "X-Webhook-Signature"
+	webhookTimestampHeader = "X-Webhook-Timestamp"
+)
+
 type PaymentHandler struct {
-	PaymentSvc service.PaymentService
-	auth       *middleware.Authentication
+	PaymentSvc  service.PaymentService
+	auth        *middleware.Authentication
+	idempotency *middleware.Idempotency
 }
 
 func (h *PaymentHandler) Router(r fiber.Router) {
+	// providers authenticate with a signature instead of a JWT, so the
+	// webhook is registered ahead of the authenticated group
+	r.Post("/payment/webhook", h.Webhook)
+
 	payment := r.Group("/payment", h.auth.JWTAuth())
 
-	payment.Post("/pay", h.Pay)
+	payment.Post("/pay", h.idempotency.WithKey(), h.Pay)
+	payment.Get("/:id/refunds", h.auth.AdminOnly(), h.ListRefunds)
+	payment.Post("/:id/refunds", h.auth.AdminOnly(), h.idempotency.WithKey(), h.Refund)
 }
 
-func ProvidePaymentHandler(svc service.PaymentService, auth *middleware.Authentication) PaymentHandler {
+func ProvidePaymentHandler(svc service.PaymentService, auth *middleware.Authentication, idempotency *middleware.Idempotency) PaymentHandler {
 	return PaymentHandler{
-		PaymentSvc: svc,
-		auth:       auth,
+		PaymentSvc:  svc,
+		auth:        auth,
+		idempotency: idempotency,
 	}
 }
 
@@ -33,22 +48,34 @@ func ProvidePaymentHandler(svc service.PaymentService, auth *middleware.Authenti
 // @Description This endpoint creates a new product
 // @Tags v1/payment
 // @Param Authorization header string true "Bearer Token"
+// @Param Idempotency-Key header string false "key to safely retry the request"
 // @Param payRequest body dto.PayRequest true "orderID to pay"
 // @Produce json
 // @Success 201 {object} response.Base{}
 // @Failure 400 {object} response.Base
+// @Failure 402 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 404 {object} response.Base
 // @Failure 409 {object} response.Base
+// @Failure 422 {object} response.Base
 // @Failure 500 {object} response.Base
 // @Router /v1/payment/pay/ [post]
 func (h *PaymentHandler) Pay(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[PayHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
 	var req dto.PayRequest
-	err := c.BodyParser(&req)
+	err = c.BodyParser(&req)
 	if err != nil {
 		log.Error().Err(err).Msg("[PayHandler] Failed Parsing Body")
 		return response.WithError(c, failure.BadRequest(err))
 	}
 
-	res, err := h.PaymentSvc.Pay(c.Context(), req)
+	res, err := h.PaymentSvc.Pay(c.Context(), req, userID)
 	if err != nil {
 		log.Error().Err(err).Msg("[PayHandler] Failed ListItems")
 		return response.WithError(c, err)
@@ -56,3 +83,104 @@ func (h *PaymentHandler) Pay(c *fiber.Ctx) error {
 
 	return response.WithJSON(c, fiber.StatusOK, res)
 }
+
+// Webhook receives asynchronous payment notifications
+// @Summary receives asynchronous payment notifications
+// @Description This endpoint receives signed payment events from payment providers
+// @Tags v1/payment
+// @Param X-Webhook-Signature header string true "hex HMAC-SHA256 of timestamp.body"
+// @Param X-Webhook-Timestamp header string true "unix timestamp of the event"
+// @Param event body dto.WebhookEvent true "payment event"
+// @Produce json
+// @Success 200 {object} response.Base{}
+// @Failure 400 {object} response.Base
+// @Failure 401 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/payment/webhook [post]
+func (h *PaymentHandler) Webhook(c *fiber.Ctx) error {
+	err := h.PaymentSvc.HandleWebhook(c.Context(), c.Body(), c.Get(webhookSignatureHeader), c.Get(webhookTimestampHeader))
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler] Failed HandleWebhook")
+		return response.WithError(c, err)
+	}
+
+	return response.WithMessage(c, fiber.StatusOK, "OK")
+}
+
+// Refund refunds a payment
+// @Summary refunds a payment
+// @Description This endpoint refunds part or all of a payment through its provider, optionally restocking the returned items. Admin only.
+// @Tags v1/payment
+// @Param Authorization header string true "Bearer Token"
+// @Param Idempotency-Key header string false "key to safely retry the request"
+// @Param id path string true "payment id"
+// @Param refundRequest body dto.RefundRequest true "amount and returned items"
+// @Produce json
+// @Success 201 {object} response.Base{data=dto.RefundResultResponse}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/payment/{id}/refunds [post]
+func (h *PaymentHandler) Refund(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[RefundHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	paymentID, err := uuid.FromString(c.Params("id"))
+	if err != nil {
+		log.Error().Err(err).Msg("[RefundHandler] Failed Parsing Payment ID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.RefundRequest
+	err = c.BodyParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[RefundHandler] Failed Parsing Body")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.PaymentSvc.Refund(c.Context(), paymentID.String(), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[RefundHandler] Failed Refund")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusCreated, res)
+}
+
+// ListRefunds lists the refunds of a payment
+// @Summary lists the refunds of a payment
+// @Description This endpoint lists the refund ledger of a payment with the amount that can still be refunded. Admin only.
+// @Tags v1/payment
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "payment id"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.RefundListResponse}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/payment/{id}/refunds [get]
+func (h *PaymentHandler) ListRefunds(c *fiber.Ctx) error {
+	paymentID, err := uuid.FromString(c.Params("id"))
+	if err != nil {
+		log.Error().Err(err).Msg("[ListRefundsHandler] Failed Parsing Payment ID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.PaymentSvc.ListRefunds(c.Context(), paymentID.String())
+	if err != nil {
+		log.Error().Err(err).Msg("[ListRefundsHandler] Failed ListRefunds")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
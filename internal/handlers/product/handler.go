@@ -5,9 +5,11 @@ import (
 	"github.com/azka-zaydan/synapsis-test/internal/domain/product/model/dto"
 	"github.com/azka-zaydan/synapsis-test/internal/domain/product/service"
 	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/jwt"
 	"github.com/azka-zaydan/synapsis-test/transport/http/middleware"
 	"github.com/azka-zaydan/synapsis-test/transport/http/response"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid"
 	"github.com/rs/zerolog/log"
 )
 
@@ -21,6 +23,11 @@ func (h *ProductHandler) Router(r fiber.Router) {
 
 	product.Post("/", h.CreateProduct)
 	product.Post("/filter", h.GetProductsByFilter)
+	product.Get("/:id", h.GetProduct)
+	product.Put("/:id", h.auth.AdminOnly(), h.UpdateProduct)
+	product.Patch("/:id", h.auth.AdminOnly(), h.PatchProduct)
+	product.Delete("/:id", h.auth.AdminOnly(), h.DeleteProduct)
+	product.Post("/:id/restore", h.auth.AdminOnly(), h.RestoreProduct)
 }
 
 func ProvideProductHandler(auth *middleware.Authentication, svc service.ProductService) ProductHandler {
@@ -32,12 +39,12 @@ func ProvideProductHandler(auth *middleware.Authentication, svc service.ProductS
 
 // GetProducts gets all products by filter
 // @Summary gets all products by filter
-// @Description This endpoint gets all products by filter
+// @Description This endpoint gets all products by filter. Page pagination, the default, returns dto.Metadata. Cursor pagination returns dto.CursorMetadata, with nextCursor and prevCursor to pass as cursor for the pages after and before, and totalData only when withTotal is set.
 // @Tags v1/product
 // @Param Authorization header string true "Bearer Token"
 // @Param Filter body model.Filter true "filter"
 // @Produce json
-// @Success 201 {object} response.Base{}
+// @Success 200 {object} response.Base{data=dto.ProductFilterResponse}
 // @Failure 400 {object} response.Base
 // @Failure 409 {object} response.Base
 // @Failure 500 {object} response.Base
@@ -88,3 +95,148 @@ func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
 	}
 	return response.WithJSON(c, fiber.StatusOK, res)
 }
+
+// GetProduct gets a product
+// @Summary gets a product
+// @Description This endpoint gets a product of the catalog. Deleted products are not found.
+// @Tags v1/product
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "product id"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.ProductResponse}
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/product/{id} [get]
+func (h *ProductHandler) GetProduct(c *fiber.Ctx) error {
+	res, err := h.service.GetProduct(c.Context(), c.Params("id"))
+	if err != nil {
+		log.Error().Err(err).Msg("[GetProductHandler] Failed GetProduct")
+		return response.WithError(c, err)
+	}
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// UpdateProduct replaces a product
+// @Summary replaces a product
+// @Description This endpoint replaces the details of a product. Admin only.
+// @Tags v1/product
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "product id"
+// @Param updateProduct body dto.ProductUpdateRequest true "update product body"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.ProductResponse}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/product/{id} [put]
+func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
+	userID, err := uuid.FromString(jwt.GetClaims(c)["userID"].(string))
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateProductHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.ProductUpdateRequest
+	err = c.BodyParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateProductHandler] Failed Parsing Body")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	res, err := h.service.UpdateProduct(c.Context(), c.Params("id"), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateProductHandler] Failed UpdateProduct")
+		return response.WithError(c, err)
+	}
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// PatchProduct changes a product
+// @Summary changes a product
+// @Description This endpoint changes the details of a product present in the body and leaves the others as they are. Admin only.
+// @Tags v1/product
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "product id"
+// @Param patchProduct body dto.ProductPatchRequest true "patch product body"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.ProductResponse}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/product/{id} [patch]
+func (h *ProductHandler) PatchProduct(c *fiber.Ctx) error {
+	userID, err := uuid.FromString(jwt.GetClaims(c)["userID"].(string))
+	if err != nil {
+		log.Error().Err(err).Msg("[PatchProductHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.ProductPatchRequest
+	err = c.BodyParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[PatchProductHandler] Failed Parsing Body")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	res, err := h.service.PatchProduct(c.Context(), c.Params("id"), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[PatchProductHandler] Failed PatchProduct")
+		return response.WithError(c, err)
+	}
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// DeleteProduct deletes a product
+// @Summary deletes a product
+// @Description This endpoint takes a product off the catalog. It can no longer be found or added to a cart, orders keep it, and it can be restored. Admin only.
+// @Tags v1/product
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "product id"
+// @Produce json
+// @Success 200 {object} response.Base{}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/product/{id} [delete]
+func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
+	userID, err := uuid.FromString(jwt.GetClaims(c)["userID"].(string))
+	if err != nil {
+		log.Error().Err(err).Msg("[DeleteProductHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	err = h.service.DeleteProduct(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeleteProductHandler] Failed DeleteProduct")
+		return response.WithError(c, err)
+	}
+	return response.WithMessage(c, fiber.StatusOK, "product deleted")
+}
+
+// RestoreProduct restores a deleted product
+// @Summary restores a deleted product
+// @Description This endpoint puts a deleted product back in the catalog. It fails with 422 when the category of the product was deleted meanwhile. Admin only.
+// @Tags v1/product
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "product id"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.ProductResponse}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/product/{id}/restore [post]
+func (h *ProductHandler) RestoreProduct(c *fiber.Ctx) error {
+	userID, err := uuid.FromString(jwt.GetClaims(c)["userID"].(string))
+	if err != nil {
+		log.Error().Err(err).Msg("[RestoreProductHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	res, err := h.service.RestoreProduct(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[RestoreProductHandler] Failed RestoreProduct")
+		return response.WithError(c, err)
+	}
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
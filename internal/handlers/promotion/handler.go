@@ -0,0 +1,90 @@
+package promotion
+
+import (
+	"github.com/azka-zaydan/synapsis-test/internal/domain/promotion/model/dto"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/promotion/service"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/jwt"
+	"github.com/azka-zaydan/synapsis-test/transport/http/middleware"
+	"github.com/azka-zaydan/synapsis-test/transport/http/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type PromotionHandler struct {
+	PromotionSvc service.PromotionService
+	auth         *middleware.Authentication
+}
+
+func (h *PromotionHandler) Router(r fiber.Router) {
+	promotion := r.Group("/promotion", h.auth.JWTAuth(), h.auth.AdminOnly())
+
+	promotion.Get("", h.ListPromotions)
+	promotion.Post("", h.CreatePromotion)
+}
+
+func ProvidePromotionHandler(svc service.PromotionService, auth *middleware.Authentication) PromotionHandler {
+	return PromotionHandler{
+		PromotionSvc: svc,
+		auth:         auth,
+	}
+}
+
+// ListPromotions lists all promotions
+// @Summary lists all promotions
+// @Description This endpoint lists all promotions. Admin only.
+// @Tags v1/promotion
+// @Param Authorization header string true "Bearer Token"
+// @Produce json
+// @Success 200 {object} response.Base{data=[]dto.PromotionResponse}
+// @Failure 403 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/promotion [get]
+func (h *PromotionHandler) ListPromotions(c *fiber.Ctx) error {
+	res, err := h.PromotionSvc.ListPromotions(c.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("[ListPromotionsHandler] Failed ListPromotions")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// CreatePromotion creates a promotion
+// @Summary creates a promotion
+// @Description This endpoint creates a percentage, fixed, free_shipping or buy_x_get_y promotion. Promotions without a code apply automatically. Admin only.
+// @Tags v1/promotion
+// @Param Authorization header string true "Bearer Token"
+// @Param createPromotionRequest body dto.CreatePromotionRequest true "promotion to create"
+// @Produce json
+// @Success 201 {object} response.Base{data=dto.PromotionResponse}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/promotion [post]
+func (h *PromotionHandler) CreatePromotion(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreatePromotionHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.CreatePromotionRequest
+	err = c.BodyParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreatePromotionHandler] Failed Parsing Body")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.PromotionSvc.CreatePromotion(c.Context(), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreatePromotionHandler] Failed CreatePromotion")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusCreated, res)
+}
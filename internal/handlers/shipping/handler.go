@@ -0,0 +1,89 @@
+package shipping
+
+import (
+	"github.com/azka-zaydan/synapsis-test/internal/domain/shipping/model/dto"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/shipping/service"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/jwt"
+	"github.com/azka-zaydan/synapsis-test/transport/http/middleware"
+	"github.com/azka-zaydan/synapsis-test/transport/http/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type ShippingHandler struct {
+	ShippingSvc service.ShippingService
+	auth        *middleware.Authentication
+}
+
+func (h *ShippingHandler) Router(r fiber.Router) {
+	shipping := r.Group("/shipping", h.auth.JWTAuth(), h.auth.AdminOnly())
+
+	shipping.Get("/rates", h.ListShippingRates)
+	shipping.Post("/rates", h.CreateShippingRate)
+}
+
+func ProvideShippingHandler(svc service.ShippingService, auth *middleware.Authentication) ShippingHandler {
+	return ShippingHandler{
+		ShippingSvc: svc,
+		auth:        auth,
+	}
+}
+
+// ListShippingRates lists all shipping rates
+// @Summary lists all shipping rates
+// @Description This endpoint lists the shipping rate table by method, newest first. Admin only.
+// @Tags v1/shipping
+// @Param Authorization header string true "Bearer Token"
+// @Produce json
+// @Success 200 {object} response.Base{data=[]dto.ShippingRateResponse}
+// @Failure 403 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/shipping/rates [get]
+func (h *ShippingHandler) ListShippingRates(c *fiber.Ctx) error {
+	res, err := h.ShippingSvc.ListShippingRates(c.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("[ListShippingRatesHandler] Failed ListShippingRates")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// CreateShippingRate creates a shipping rate
+// @Summary creates a shipping rate
+// @Description This endpoint adds a row to the shipping rate table. For each method the rate for the destination country wins over a rate for all countries, and of two such rates the newest. Admin only.
+// @Tags v1/shipping
+// @Param Authorization header string true "Bearer Token"
+// @Param createShippingRateRequest body dto.CreateShippingRateRequest true "shipping rate to create"
+// @Produce json
+// @Success 201 {object} response.Base{data=dto.ShippingRateResponse}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/shipping/rates [post]
+func (h *ShippingHandler) CreateShippingRate(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateShippingRateHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.CreateShippingRateRequest
+	err = c.BodyParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateShippingRateHandler] Failed Parsing Body")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.ShippingSvc.CreateShippingRate(c.Context(), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateShippingRateHandler] Failed CreateShippingRate")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusCreated, res)
+}
@@ -0,0 +1,89 @@
+package tax
+
+import (
+	"github.com/azka-zaydan/synapsis-test/internal/domain/tax/model/dto"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/tax/service"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/jwt"
+	"github.com/azka-zaydan/synapsis-test/transport/http/middleware"
+	"github.com/azka-zaydan/synapsis-test/transport/http/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type TaxHandler struct {
+	TaxSvc service.TaxService
+	auth   *middleware.Authentication
+}
+
+func (h *TaxHandler) Router(r fiber.Router) {
+	tax := r.Group("/tax", h.auth.JWTAuth(), h.auth.AdminOnly())
+
+	tax.Get("/rates", h.ListTaxRates)
+	tax.Post("/rates", h.CreateTaxRate)
+}
+
+func ProvideTaxHandler(svc service.TaxService, auth *middleware.Authentication) TaxHandler {
+	return TaxHandler{
+		TaxSvc: svc,
+		auth:   auth,
+	}
+}
+
+// ListTaxRates lists all tax rates
+// @Summary lists all tax rates
+// @Description This endpoint lists all tax rates, newest first. Admin only.
+// @Tags v1/tax
+// @Param Authorization header string true "Bearer Token"
+// @Produce json
+// @Success 200 {object} response.Base{data=[]dto.TaxRateResponse}
+// @Failure 403 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/tax/rates [get]
+func (h *TaxHandler) ListTaxRates(c *fiber.Ctx) error {
+	res, err := h.TaxSvc.ListTaxRates(c.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("[ListTaxRatesHandler] Failed ListTaxRates")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// CreateTaxRate creates a tax rate
+// @Summary creates a tax rate
+// @Description This endpoint creates a tax rate for a category and a region. The most specific rate applies, and of equally specific rates the newest. Admin only.
+// @Tags v1/tax
+// @Param Authorization header string true "Bearer Token"
+// @Param createTaxRateRequest body dto.CreateTaxRateRequest true "tax rate to create"
+// @Produce json
+// @Success 201 {object} response.Base{data=dto.TaxRateResponse}
+// @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/tax/rates [post]
+func (h *TaxHandler) CreateTaxRate(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateTaxRateHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.CreateTaxRateRequest
+	err = c.BodyParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateTaxRateHandler] Failed Parsing Body")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.TaxSvc.CreateTaxRate(c.Context(), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateTaxRateHandler] Failed CreateTaxRate")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusCreated, res)
+}
@@ -0,0 +1,197 @@
+package user
+
+import (
+	"github.com/azka-zaydan/synapsis-test/internal/domain/user/model/dto"
+	"github.com/azka-zaydan/synapsis-test/internal/domain/user/service"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/jwt"
+	"github.com/azka-zaydan/synapsis-test/transport/http/middleware"
+	"github.com/azka-zaydan/synapsis-test/transport/http/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type UserHandler struct {
+	UserSvc service.UserService
+	auth    *middleware.Authentication
+}
+
+func (h *UserHandler) Router(r fiber.Router) {
+	user := r.Group("/user", h.auth.JWTAuth())
+
+	user.Get("/addresses", h.ListAddresses)
+	user.Post("/addresses", h.CreateAddress)
+	user.Get("/addresses/:id", h.GetAddress)
+	user.Put("/addresses/:id", h.UpdateAddress)
+	user.Delete("/addresses/:id", h.DeleteAddress)
+}
+
+func ProvideUserHandler(svc service.UserService, auth *middleware.Authentication) UserHandler {
+	return UserHandler{
+		UserSvc: svc,
+		auth:    auth,
+	}
+}
+
+// ListAddresses lists the address book of the user
+// @Summary lists the address book of the user
+// @Description This endpoint lists the addresses of the logged in user, the default address first
+// @Tags v1/user
+// @Param Authorization header string true "Bearer Token"
+// @Produce json
+// @Success 200 {object} response.Base{data=[]dto.AddressResponse}
+// @Failure 400 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/user/addresses [get]
+func (h *UserHandler) ListAddresses(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[ListAddressesHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.UserSvc.ListAddresses(c.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[ListAddressesHandler] Failed ListAddresses")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// GetAddress gets an address of the user
+// @Summary gets an address of the user
+// @Description This endpoint gets an address from the address book of the logged in user
+// @Tags v1/user
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "address id"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.AddressResponse}
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/user/addresses/{id} [get]
+func (h *UserHandler) GetAddress(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[GetAddressHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.UserSvc.GetAddress(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[GetAddressHandler] Failed GetAddress")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// CreateAddress adds an address to the address book
+// @Summary adds an address to the address book
+// @Description This endpoint adds an address to the address book of the logged in user. The first address becomes the default, marking another address as default unmarks the previous one.
+// @Tags v1/user
+// @Param Authorization header string true "Bearer Token"
+// @Param addressRequest body dto.AddressRequest true "address to add"
+// @Produce json
+// @Success 201 {object} response.Base{data=dto.AddressResponse}
+// @Failure 400 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/user/addresses [post]
+func (h *UserHandler) CreateAddress(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateAddressHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.AddressRequest
+	err = c.BodyParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateAddressHandler] Failed Parsing Body")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.UserSvc.CreateAddress(c.Context(), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[CreateAddressHandler] Failed CreateAddress")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusCreated, res)
+}
+
+// UpdateAddress replaces an address of the user
+// @Summary replaces an address of the user
+// @Description This endpoint replaces an address in the address book of the logged in user. Orders already placed keep the address they were placed with.
+// @Tags v1/user
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "address id"
+// @Param addressRequest body dto.AddressRequest true "new address"
+// @Produce json
+// @Success 200 {object} response.Base{data=dto.AddressResponse}
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/user/addresses/{id} [put]
+func (h *UserHandler) UpdateAddress(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateAddressHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+	var req dto.AddressRequest
+	err = c.BodyParser(&req)
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateAddressHandler] Failed Parsing Body")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	res, err := h.UserSvc.UpdateAddress(c.Context(), c.Params("id"), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[UpdateAddressHandler] Failed UpdateAddress")
+		return response.WithError(c, err)
+	}
+
+	return response.WithJSON(c, fiber.StatusOK, res)
+}
+
+// DeleteAddress removes an address from the address book
+// @Summary removes an address from the address book
+// @Description This endpoint removes an address from the address book of the logged in user
+// @Tags v1/user
+// @Param Authorization header string true "Bearer Token"
+// @Param id path string true "address id"
+// @Produce json
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 500 {object} response.Base
+// @Router /v1/user/addresses/{id} [delete]
+func (h *UserHandler) DeleteAddress(c *fiber.Ctx) error {
+	userIDStr := jwt.GetClaims(c)["userID"].(string)
+
+	userID, err := uuid.FromString(userIDStr)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeleteAddressHandler] Failed Converting into UUID")
+		return response.WithError(c, failure.BadRequest(err))
+	}
+
+	err = h.UserSvc.DeleteAddress(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeleteAddressHandler] Failed DeleteAddress")
+		return response.WithError(c, err)
+	}
+
+	return response.WithMessage(c, fiber.StatusOK, "address deleted")
+}
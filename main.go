@@ -25,6 +25,10 @@ func main() {
 	// Wire everything up
 	http := InitializeService()
 
+	// Start background jobs
+	workers := InitializeWorker()
+	workers.Start()
+
 	// consumers := InitializeEvent()
 
 	// // Start consumers
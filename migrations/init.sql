@@ -3,6 +3,7 @@ CREATE TABLE IF NOT EXISTS user (
     id CHAR(36) PRIMARY KEY NOT NULL,
     username VARCHAR(255) NOT NULL,
     password_hash TEXT NOT NULL,
+    role VARCHAR(32) NOT NULL DEFAULT 'customer',
     created_by CHAR(36) NOT NULL,
     meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_by CHAR(36) NOT NULL,
@@ -18,6 +19,13 @@ CREATE TABLE IF NOT EXISTS `order` (
     user_id CHAR(36) NOT NULL,
     payment_id CHAR(36) NOT NULL,
     total_price DECIMAL(10, 2) NOT NULL,
+    discount_total DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    tax_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    shipping_method VARCHAR(64) NOT NULL DEFAULT '',
+    shipping_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    shipping_address JSON,
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    conversion_snapshot JSON,
     status INT NOT NULL,
     order_at TIMESTAMP NOT NULL,
     payment_at TIMESTAMP,
@@ -29,6 +37,8 @@ CREATE TABLE IF NOT EXISTS `order` (
     deleted_by CHAR(36),
     meta_deleted_at TIMESTAMP,
     INDEX idx_user_id (user_id),
+    INDEX idx_user_id_order_at (user_id, order_at),
+    INDEX idx_status_order_at (status, order_at),
     INDEX idx_payment_id (payment_id),
     INDEX idx_created_by (created_by)
 );
@@ -36,6 +46,7 @@ CREATE TABLE IF NOT EXISTS `order` (
 -- Category Table
 CREATE TABLE IF NOT EXISTS category (
     id CHAR(36) PRIMARY KEY NOT NULL,
+    parent_id CHAR(36),
     name VARCHAR(255) NOT NULL,
     created_by CHAR(36) NOT NULL,
     meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
@@ -43,6 +54,7 @@ CREATE TABLE IF NOT EXISTS category (
     meta_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     deleted_by CHAR(36),
     meta_deleted_at TIMESTAMP,
+    INDEX idx_parent_id (parent_id),
     INDEX idx_created_by (created_by)
 );
 
@@ -53,7 +65,9 @@ CREATE TABLE IF NOT EXISTS product (
     name VARCHAR(255) NOT NULL,
     description VARCHAR(255) NOT NULL,
     price DECIMAL(10, 2) NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
     stock INT NOT NULL,
+    weight INT NOT NULL DEFAULT 0,
     created_by CHAR(36) NOT NULL,
     meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_by CHAR(36) NOT NULL,
@@ -62,6 +76,9 @@ CREATE TABLE IF NOT EXISTS product (
     meta_deleted_at TIMESTAMP,
     INDEX idx_category_id (category_id),
     INDEX idx_name (name),
+    INDEX idx_price (price),
+    INDEX idx_stock (stock),
+    INDEX idx_meta_created_at (meta_created_at),
     INDEX idx_created_by (created_by)
 );
 
@@ -71,6 +88,7 @@ CREATE TABLE IF NOT EXISTS cart (
     user_id CHAR(36) NOT NULL,
     total_items INT NOT NULL,
     total_price DECIMAL(10, 2) NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
     created_by CHAR(36) NOT NULL,
     meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_by CHAR(36) NOT NULL,
@@ -105,7 +123,10 @@ CREATE TABLE IF NOT EXISTS payment (
     order_id char(36) NOT NULL,
     user_id CHAR(36) NOT NULL,
     payment_method CHAR(36) NOT NULL,
+    provider_reference VARCHAR(255),
     total_price DECIMAL(10, 2) NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    conversion_snapshot JSON,
     status INT NOT NULL,
     payment_at TIMESTAMP,
     created_by CHAR(36) NOT NULL,
@@ -115,7 +136,9 @@ CREATE TABLE IF NOT EXISTS payment (
     deleted_by CHAR(36),
     meta_deleted_at TIMESTAMP,
     INDEX idx_user_id (user_id),
+    INDEX idx_order_id (order_id),
     INDEX idx_payment_method (payment_method),
+    INDEX idx_provider_reference (provider_reference),
     INDEX idx_created_by (created_by)
 );
 
@@ -124,8 +147,34 @@ CREATE TABLE IF NOT EXISTS order_detail (
     id CHAR(36) PRIMARY KEY NOT NULL,
     order_id CHAR(36) NOT NULL,
     product_id CHAR(36) NOT NULL,
+    product_name VARCHAR(255) NOT NULL,
+    category_id CHAR(36) NOT NULL,
+    category_name VARCHAR(255) NOT NULL,
+    unit_price DECIMAL(10, 2) NOT NULL,
     total_items INT NOT NULL,
     subtotal_product_price DECIMAL(10, 2) NOT NULL,
+    tax_rate INT NOT NULL DEFAULT 0,
+    tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    tax_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    created_by CHAR(36) NOT NULL,
+    meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by CHAR(36) NOT NULL,
+    meta_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_by CHAR(36),
+    meta_deleted_at TIMESTAMP,
+    INDEX idx_order_id (order_id),
+    INDEX idx_product_id (product_id),
+    INDEX idx_created_by (created_by)
+);
+
+-- Reservation Table
+CREATE TABLE IF NOT EXISTS reservation (
+    id CHAR(36) PRIMARY KEY NOT NULL,
+    order_id CHAR(36) NOT NULL,
+    product_id CHAR(36) NOT NULL,
+    quantity INT NOT NULL,
+    status INT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
     created_by CHAR(36) NOT NULL,
     meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_by CHAR(36) NOT NULL,
@@ -134,5 +183,212 @@ CREATE TABLE IF NOT EXISTS order_detail (
     meta_deleted_at TIMESTAMP,
     INDEX idx_order_id (order_id),
     INDEX idx_product_id (product_id),
+    INDEX idx_status_expires_at (status, expires_at)
+);
+
+-- Order Status History Table
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id CHAR(36) PRIMARY KEY NOT NULL,
+    order_id CHAR(36) NOT NULL,
+    from_status INT,
+    to_status INT NOT NULL,
+    created_by CHAR(36) NOT NULL,
+    meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by CHAR(36) NOT NULL,
+    meta_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_by CHAR(36),
+    meta_deleted_at TIMESTAMP,
+    INDEX idx_order_id (order_id),
     INDEX idx_created_by (created_by)
 );
+
+-- Refund Table
+CREATE TABLE IF NOT EXISTS refund (
+    id CHAR(36) PRIMARY KEY NOT NULL,
+    payment_id CHAR(36) NOT NULL,
+    order_id CHAR(36) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    status INT NOT NULL,
+    provider_reference VARCHAR(255),
+    restock BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by CHAR(36) NOT NULL,
+    meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by CHAR(36) NOT NULL,
+    meta_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_by CHAR(36),
+    meta_deleted_at TIMESTAMP,
+    INDEX idx_payment_id (payment_id),
+    INDEX idx_order_id (order_id)
+);
+
+-- Refund Item Table
+CREATE TABLE IF NOT EXISTS refund_item (
+    id CHAR(36) PRIMARY KEY NOT NULL,
+    refund_id CHAR(36) NOT NULL,
+    order_detail_id CHAR(36) NOT NULL,
+    product_id CHAR(36) NOT NULL,
+    quantity INT NOT NULL,
+    created_by CHAR(36) NOT NULL,
+    meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by CHAR(36) NOT NULL,
+    meta_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_by CHAR(36),
+    meta_deleted_at TIMESTAMP,
+    INDEX idx_refund_id (refund_id),
+    INDEX idx_order_detail_id (order_detail_id)
+);
+
+-- Exchange Rate Table
+CREATE TABLE IF NOT EXISTS exchange_rate (
+    id CHAR(36) PRIMARY KEY NOT NULL,
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate DECIMAL(18, 8) NOT NULL,
+    effective_at TIMESTAMP NOT NULL,
+    created_by CHAR(36) NOT NULL,
+    meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by CHAR(36) NOT NULL,
+    meta_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_by CHAR(36),
+    meta_deleted_at TIMESTAMP,
+    INDEX idx_pair_effective_at (base_currency, quote_currency, effective_at)
+);
+
+-- Promotion Table
+CREATE TABLE IF NOT EXISTS promotion (
+    id CHAR(36) PRIMARY KEY NOT NULL,
+    code VARCHAR(64),
+    name VARCHAR(255) NOT NULL,
+    type INT NOT NULL,
+    percent_off INT NOT NULL DEFAULT 0,
+    amount_off DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    buy_quantity INT NOT NULL DEFAULT 0,
+    get_quantity INT NOT NULL DEFAULT 0,
+    category_id CHAR(36),
+    min_subtotal DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP NULL,
+    ends_at TIMESTAMP NULL,
+    usage_limit INT,
+    per_user_limit INT,
+    stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by CHAR(36) NOT NULL,
+    meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by CHAR(36) NOT NULL,
+    meta_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_by CHAR(36),
+    meta_deleted_at TIMESTAMP,
+    UNIQUE INDEX idx_code (code),
+    INDEX idx_starts_at_ends_at (starts_at, ends_at)
+);
+
+-- Promotion Redemption Table
+CREATE TABLE IF NOT EXISTS promotion_redemption (
+    id CHAR(36) PRIMARY KEY NOT NULL,
+    promotion_id CHAR(36) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    order_id CHAR(36) NOT NULL,
+    created_by CHAR(36) NOT NULL,
+    meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by CHAR(36) NOT NULL,
+    meta_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_by CHAR(36),
+    meta_deleted_at TIMESTAMP,
+    INDEX idx_promotion_id_user_id (promotion_id, user_id),
+    INDEX idx_order_id (order_id)
+);
+
+-- Cart Coupon Table
+CREATE TABLE IF NOT EXISTS cart_coupon (
+    id CHAR(36) PRIMARY KEY NOT NULL,
+    cart_id CHAR(36) NOT NULL,
+    promotion_id CHAR(36) NOT NULL,
+    code VARCHAR(64) NOT NULL,
+    created_by CHAR(36) NOT NULL,
+    meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by CHAR(36) NOT NULL,
+    meta_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_by CHAR(36),
+    meta_deleted_at TIMESTAMP,
+    UNIQUE INDEX idx_cart_id_promotion_id (cart_id, promotion_id)
+);
+
+-- Order Discount Table
+CREATE TABLE IF NOT EXISTS order_discount (
+    id CHAR(36) PRIMARY KEY NOT NULL,
+    order_id CHAR(36) NOT NULL,
+    promotion_id CHAR(36) NOT NULL,
+    code VARCHAR(64),
+    name VARCHAR(255) NOT NULL,
+    type INT NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    free_shipping BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by CHAR(36) NOT NULL,
+    meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by CHAR(36) NOT NULL,
+    meta_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_by CHAR(36),
+    meta_deleted_at TIMESTAMP,
+    INDEX idx_order_id (order_id),
+    INDEX idx_promotion_id (promotion_id)
+);
+
+-- Tax Rate Table
+CREATE TABLE IF NOT EXISTS tax_rate (
+    id CHAR(36) PRIMARY KEY NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    category_id CHAR(36),
+    region VARCHAR(64),
+    rate INT NOT NULL,
+    inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by CHAR(36) NOT NULL,
+    meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by CHAR(36) NOT NULL,
+    meta_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_by CHAR(36),
+    meta_deleted_at TIMESTAMP,
+    INDEX idx_region_category_id (region, category_id)
+);
+
+-- Address Table
+CREATE TABLE IF NOT EXISTS address (
+    id CHAR(36) PRIMARY KEY NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    label VARCHAR(64) NOT NULL DEFAULT '',
+    recipient_name VARCHAR(255) NOT NULL,
+    phone VARCHAR(32) NOT NULL DEFAULT '',
+    line1 VARCHAR(255) NOT NULL,
+    line2 VARCHAR(255) NOT NULL DEFAULT '',
+    city VARCHAR(255) NOT NULL,
+    region VARCHAR(255) NOT NULL DEFAULT '',
+    postal_code VARCHAR(32) NOT NULL DEFAULT '',
+    country CHAR(2) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by CHAR(36) NOT NULL,
+    meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by CHAR(36) NOT NULL,
+    meta_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_by CHAR(36),
+    meta_deleted_at TIMESTAMP,
+    INDEX idx_user_id (user_id)
+);
+
+-- Shipping Rate Table
+CREATE TABLE IF NOT EXISTS shipping_rate (
+    id CHAR(36) PRIMARY KEY NOT NULL,
+    method VARCHAR(64) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    zone VARCHAR(64),
+    min_weight INT NOT NULL DEFAULT 0,
+    max_weight INT,
+    price DECIMAL(10, 2) NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    created_by CHAR(36) NOT NULL,
+    meta_created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by CHAR(36) NOT NULL,
+    meta_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_by CHAR(36),
+    meta_deleted_at TIMESTAMP,
+    INDEX idx_zone_method (zone, method)
+);
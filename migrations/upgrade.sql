@@ -0,0 +1,75 @@
+-- Upgrades a database created from the first version of init.sql. Run it
+-- once, then run init.sql to create the tables added since.
+
+-- User Table
+ALTER TABLE user
+    ADD COLUMN role VARCHAR(32) NOT NULL DEFAULT 'customer' AFTER password_hash;
+
+-- Order Table
+ALTER TABLE `order`
+    ADD COLUMN discount_total DECIMAL(10, 2) NOT NULL DEFAULT 0 AFTER total_price,
+    ADD COLUMN tax_amount DECIMAL(10, 2) NOT NULL DEFAULT 0 AFTER discount_total,
+    ADD COLUMN shipping_method VARCHAR(64) NOT NULL DEFAULT '' AFTER tax_amount,
+    ADD COLUMN shipping_amount DECIMAL(10, 2) NOT NULL DEFAULT 0 AFTER shipping_method,
+    ADD COLUMN shipping_address JSON AFTER shipping_amount,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER shipping_address,
+    ADD COLUMN conversion_snapshot JSON AFTER currency,
+    ADD INDEX idx_user_id_order_at (user_id, order_at),
+    ADD INDEX idx_status_order_at (status, order_at);
+
+-- Category Table
+ALTER TABLE category
+    ADD COLUMN parent_id CHAR(36) AFTER id,
+    ADD INDEX idx_parent_id (parent_id);
+
+-- Product Table
+ALTER TABLE product
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER price,
+    ADD COLUMN weight INT NOT NULL DEFAULT 0 AFTER stock,
+    ADD INDEX idx_price (price),
+    ADD INDEX idx_stock (stock),
+    ADD INDEX idx_meta_created_at (meta_created_at);
+
+-- Cart Table
+ALTER TABLE cart
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER total_price;
+
+-- Payment Table
+ALTER TABLE payment
+    ADD COLUMN provider_reference VARCHAR(255) AFTER payment_method,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER total_price,
+    ADD COLUMN conversion_snapshot JSON AFTER currency,
+    ADD INDEX idx_order_id (order_id),
+    ADD INDEX idx_provider_reference (provider_reference);
+
+-- Order Detail Table
+-- Existing lines get the product and category as they are now and the
+-- unit price they were sold at.
+ALTER TABLE order_detail
+    ADD COLUMN product_name VARCHAR(255) NOT NULL DEFAULT '' AFTER product_id,
+    ADD COLUMN category_id CHAR(36) NOT NULL DEFAULT '' AFTER product_name,
+    ADD COLUMN category_name VARCHAR(255) NOT NULL DEFAULT '' AFTER category_id,
+    ADD COLUMN unit_price DECIMAL(10, 2) NOT NULL DEFAULT 0 AFTER category_name,
+    ADD COLUMN tax_rate INT NOT NULL DEFAULT 0 AFTER subtotal_product_price,
+    ADD COLUMN tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE AFTER tax_rate,
+    ADD COLUMN tax_amount DECIMAL(10, 2) NOT NULL DEFAULT 0 AFTER tax_inclusive;
+
+UPDATE order_detail od
+JOIN product p ON p.id = od.product_id
+SET
+    od.product_name = p.name,
+    od.category_id = p.category_id;
+
+UPDATE order_detail od
+JOIN category c ON c.id = od.category_id
+SET od.category_name = c.name;
+
+UPDATE order_detail
+SET unit_price = subtotal_product_price / total_items
+WHERE total_items > 0;
+
+ALTER TABLE order_detail
+    ALTER COLUMN product_name DROP DEFAULT,
+    ALTER COLUMN category_id DROP DEFAULT,
+    ALTER COLUMN category_name DROP DEFAULT,
+    ALTER COLUMN unit_price DROP DEFAULT;
@@ -43,6 +43,14 @@ func Unauthorized(msg string) error {
 	}
 }
 
+// Forbidden returns a new Failure with code for requests on resources the caller does not own.
+func Forbidden(msg string) error {
+	return &Failure{
+		Code:    http.StatusForbidden,
+		Message: msg,
+	}
+}
+
 // InternalError returns a new Failure with code for internal error and message derived from an error interface.
 func InternalError(err error) error {
 	if err != nil {
@@ -78,6 +86,22 @@ func Conflict(operationName string, entityName string, message string) error {
 	}
 }
 
+// PaymentRequired returns a new Failure with code for payments the provider refused.
+func PaymentRequired(msg string) error {
+	return &Failure{
+		Code:    http.StatusPaymentRequired,
+		Message: msg,
+	}
+}
+
+// UnprocessableEntity returns a new Failure with code for well-formed requests that cannot be processed.
+func UnprocessableEntity(msg string) error {
+	return &Failure{
+		Code:    http.StatusUnprocessableEntity,
+		Message: msg,
+	}
+}
+
 // GetCode returns the error code of an error interface.
 func GetCode(err error) int {
 	if f, ok := err.(*Failure); ok {
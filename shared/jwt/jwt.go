@@ -17,6 +17,7 @@ type JwtService struct {
 type Claims struct {
 	UserID   string `json:"userID"`
 	Username string `json:"username"`
+	Role     string `json:"role"`
 	jwtV5.RegisteredClaims
 }
 
@@ -28,11 +29,12 @@ func NewJwtService(cfg *configs.Config) *JwtService {
 	}
 }
 
-func (s *JwtService) GenerateJWT(username, id string) (string, error) {
+func (s *JwtService) GenerateJWT(username, id, role string) (string, error) {
 	expirationTime := time.Now().Add(s.cfg.JWT.ExpiresIn)
 	claims := &Claims{
 		Username: username,
 		UserID:   id,
+		Role:     role,
 		RegisteredClaims: jwtV5.RegisteredClaims{
 			ExpiresAt: jwtV5.NewNumericDate(expirationTime),
 		},
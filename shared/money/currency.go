@@ -0,0 +1,197 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// RateScale is the number of decimal places kept for exchange rates,
+// matching the DECIMAL(18, 8) rate column.
+const RateScale = 8
+
+var ErrInvalidCurrency = errors.New("money: currency must be a 3 letter ISO 4217 code")
+
+// Currency is an ISO 4217 currency code, e.g. "IDR".
+type Currency string
+
+// DefaultCurrency is used when no currency is configured.
+const DefaultCurrency Currency = "IDR"
+
+// ParseCurrency normalizes and validates a currency code.
+func ParseCurrency(s string) (Currency, error) {
+	code := strings.ToUpper(strings.TrimSpace(s))
+	if len(code) != 3 {
+		return "", ErrInvalidCurrency
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", ErrInvalidCurrency
+		}
+	}
+	return Currency(code), nil
+}
+
+func (c Currency) String() string {
+	return string(c)
+}
+
+// Rate is an exact exchange rate: one unit of the base currency is worth
+// Rate units of the quote currency.
+type Rate int64
+
+// ParseRate reads a decimal rate with up to 8 decimal places.
+func ParseRate(s string) (Rate, error) {
+	value, err := parseFixed(s, RateScale)
+	if err == errTooPrecise {
+		return 0, fmt.Errorf("money: rate has more than %d decimal places", RateScale)
+	}
+	return Rate(value), err
+}
+
+func (r Rate) String() string {
+	return formatFixed(int64(r), RateScale)
+}
+
+func (r Rate) IsPositive() bool {
+	return r > 0
+}
+
+// MarshalJSON writes the rate as a JSON number.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding one.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	s = strings.Trim(s, `"`)
+	if strings.ContainsAny(s, "eE") {
+		return ErrInvalid
+	}
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns.
+func (r *Rate) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		parsed, err := ParseRate(string(v))
+		if err != nil {
+			return err
+		}
+		*r = parsed
+		return nil
+	case string:
+		parsed, err := ParseRate(v)
+		if err != nil {
+			return err
+		}
+		*r = parsed
+		return nil
+	default:
+		return fmt.Errorf("money: cannot scan %T into a rate", src)
+	}
+}
+
+// Value implements driver.Valuer, writing the rate as a decimal string.
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Conversion records the exchange rate used to price an amount of one
+// currency in another. Inverted conversions divide by a rate that was
+// loaded for the opposite direction instead of multiplying by it.
+type Conversion struct {
+	From        Currency  `json:"from"`
+	To          Currency  `json:"to"`
+	Rate        Rate      `json:"rate"`
+	Inverted    bool      `json:"inverted"`
+	EffectiveAt time.Time `json:"effectiveAt"`
+}
+
+// Apply converts an amount of From into To, rounding half away from zero to
+// the nearest minor unit.
+func (c Conversion) Apply(a Amount) Amount {
+	if c.From == c.To {
+		return a
+	}
+	num := big.NewInt(int64(a))
+	den := big.NewInt(pow10(RateScale))
+	if c.Inverted {
+		num.Mul(num, den)
+		den = big.NewInt(int64(c.Rate))
+	} else {
+		num.Mul(num, big.NewInt(int64(c.Rate)))
+	}
+	return Amount(divRound(num, den).Int64())
+}
+
+// divRound divides rounding half away from zero.
+func divRound(num, den *big.Int) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(new(big.Int).Abs(den)) >= 0 {
+		if num.Sign()*den.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo
+}
+
+// Conversions is the set of exchange rates a priced document was computed
+// with, stored as JSON so the totals can be explained later.
+type Conversions []Conversion
+
+// Add records c unless a conversion between the same currencies is present.
+func (cs *Conversions) Add(c Conversion) {
+	if c.From == c.To {
+		return
+	}
+	for _, existing := range *cs {
+		if existing.From == c.From && existing.To == c.To {
+			return
+		}
+	}
+	*cs = append(*cs, c)
+}
+
+// Scan implements sql.Scanner for JSON columns. NULL scans as empty.
+func (cs *Conversions) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*cs = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, cs)
+	case string:
+		return json.Unmarshal([]byte(v), cs)
+	default:
+		return fmt.Errorf("money: cannot scan %T into conversions", src)
+	}
+}
+
+// Value implements driver.Valuer, writing the conversions as JSON.
+func (cs Conversions) Value() (driver.Value, error) {
+	if cs == nil {
+		cs = Conversions{}
+	}
+	data, err := json.Marshal(cs)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
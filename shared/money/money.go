@@ -0,0 +1,247 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimal places kept, matching the DECIMAL(10, 2)
+// columns of the schema.
+const Scale = 2
+
+const minorPerUnit = 100
+
+var (
+	ErrInvalid   = errors.New("money: invalid amount")
+	ErrPrecision = errors.New("money: amount has more than 2 decimal places")
+)
+
+// Amount is an exact monetary amount stored in minor units, e.g. cents.
+// Amounts compare with the usual operators and are written to SQL and JSON
+// as decimals, so callers never go through float64.
+type Amount int64
+
+// FromMinor returns the amount of minor units.
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// FromUnits returns the amount of whole units.
+func FromUnits(units int64) Amount {
+	return Amount(units * minorPerUnit)
+}
+
+// Parse reads a decimal such as "12", "12.5" or "-0.25".
+func Parse(s string) (Amount, error) {
+	minor, err := parseFixed(s, Scale)
+	if err == errTooPrecise {
+		return 0, ErrPrecision
+	}
+	return Amount(minor), err
+}
+
+var errTooPrecise = errors.New("money: too many decimal places")
+
+// parseFixed reads a decimal into an integer scaled by 10^scale.
+func parseFixed(s string, scale int) (int64, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, ErrInvalid
+	}
+	// MySQL may render DECIMAL results with a wider scale, e.g. SUM or AVG
+	trimmed := strings.TrimRight(frac, "0")
+	if len(trimmed) > scale {
+		return 0, errTooPrecise
+	}
+	frac = trimmed + strings.Repeat("0", scale-len(trimmed))
+
+	var units int64
+	if whole != "" {
+		if !isDigits(whole) {
+			return 0, ErrInvalid
+		}
+		var err error
+		units, err = strconv.ParseInt(whole, 10, 64)
+		if err != nil {
+			return 0, ErrInvalid
+		}
+	}
+	if !isDigits(frac) {
+		return 0, ErrInvalid
+	}
+	var fraction int64
+	if frac != "" {
+		fraction, _ = strconv.ParseInt(frac, 10, 64)
+	}
+	factor := pow10(scale)
+	if units > (math.MaxInt64-fraction)/factor {
+		return 0, ErrInvalid
+	}
+
+	value := units*factor + fraction
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// formatFixed renders an integer scaled by 10^scale as a decimal.
+func formatFixed(value int64, scale int) string {
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	factor := pow10(scale)
+	if scale == 0 {
+		return fmt.Sprintf("%s%d", sign, value)
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, value/factor, scale, value%factor)
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Minor returns the amount in minor units.
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Mul returns the amount times quantity, e.g. the total of a line.
+func (a Amount) Mul(quantity int) Amount {
+	return a * Amount(quantity)
+}
+
+// Percent returns basisPoints/10000 of the amount, e.g. 1250 for 12.5%,
+// rounded half away from zero to the nearest minor unit.
+func (a Amount) Percent(basisPoints int) Amount {
+	return a.MulDiv(int64(basisPoints), 10000)
+}
+
+// MulDiv returns the amount times num/den, rounded half away from zero to
+// the nearest minor unit.
+func (a Amount) MulDiv(num int64, den int64) Amount {
+	product := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(num))
+	return Amount(divRound(product, big.NewInt(den)).Int64())
+}
+
+// Allocate splits total across parts in proportion to weights, e.g. an
+// order discount across its lines. The shares always add up to total; the
+// minor units left over by rounding go to the first parts.
+func Allocate(total Amount, weights []Amount) []Amount {
+	shares := make([]Amount, len(weights))
+	var sum Amount
+	for _, w := range weights {
+		sum += w
+	}
+	if sum <= 0 {
+		return shares
+	}
+	remaining := total
+	for i, w := range weights {
+		share := new(big.Int).Mul(big.NewInt(int64(total)), big.NewInt(int64(w)))
+		share.Quo(share, big.NewInt(int64(sum)))
+		shares[i] = Amount(share.Int64())
+		remaining -= shares[i]
+	}
+	for i := 0; remaining > 0 && i < len(shares); i++ {
+		if weights[i] <= 0 {
+			continue
+		}
+		shares[i]++
+		remaining--
+	}
+	return shares
+}
+
+func (a Amount) IsPositive() bool {
+	return a > 0
+}
+
+// String renders the amount as a decimal with two places, e.g. "-12.50".
+func (a Amount) String() string {
+	return formatFixed(int64(a), Scale)
+}
+
+// MarshalJSON writes the amount as a JSON number, e.g. 12.50.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding one. The literal
+// is parsed as text so it never goes through float64.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	s = strings.Trim(s, `"`)
+	if strings.ContainsAny(s, "eE") {
+		return ErrInvalid
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns. NULL scans as zero.
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case []byte:
+		parsed, err := Parse(string(v))
+		if err != nil {
+			return err
+		}
+		*a = parsed
+		return nil
+	case string:
+		parsed, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*a = parsed
+		return nil
+	case int64:
+		*a = FromUnits(v)
+		return nil
+	case float64:
+		*a = Amount(math.Round(v * minorPerUnit))
+		return nil
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+}
+
+// Value implements driver.Valuer, writing the amount as a decimal string.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
@@ -2,6 +2,10 @@ package middleware
 
 import (
 	"github.com/azka-zaydan/synapsis-test/configs"
+	userModel "github.com/azka-zaydan/synapsis-test/internal/domain/user/model"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/jwt"
+	"github.com/azka-zaydan/synapsis-test/transport/http/response"
 	"github.com/gofiber/fiber/v2"
 	jwtware "github.com/gofiber/jwt/v3"
 )
@@ -28,3 +32,15 @@ func (m *Authentication) JWTAuth() fiber.Handler {
 		},
 	})
 }
+
+// AdminOnly refuses callers whose token does not carry the admin role. It
+// must run after JWTAuth.
+func (m *Authentication) AdminOnly() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, _ := jwt.GetClaims(c)["role"].(string)
+		if role != userModel.RoleAdmin {
+			return response.WithError(c, failure.Forbidden("admin role is required"))
+		}
+		return c.Next()
+	}
+}
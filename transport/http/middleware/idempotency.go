@@ -0,0 +1,194 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/configs"
+	"github.com/azka-zaydan/synapsis-test/infras"
+	"github.com/azka-zaydan/synapsis-test/shared/failure"
+	"github.com/azka-zaydan/synapsis-test/shared/jwt"
+	"github.com/azka-zaydan/synapsis-test/transport/http/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks responses served from a stored result.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// idempotencyLockTTL bounds how long a crashed request can keep a key
+	// busy. The lock of a running request is renewed every
+	// idempotencyLockRenewal, however long it runs.
+	idempotencyLockTTL       = time.Minute
+	idempotencyLockRenewal   = idempotencyLockTTL / 3
+	defaultIdempotencyExpiry = 24 * time.Hour
+)
+
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType"`
+	Body        []byte `json:"body"`
+}
+
+type Idempotency struct {
+	cfg   *configs.Config
+	redis *infras.Redis
+}
+
+func ProvideIdempotency(cfg *configs.Config, redis *infras.Redis) *Idempotency {
+	return &Idempotency{
+		cfg:   cfg,
+		redis: redis,
+	}
+}
+
+// WithKey makes a route safe to retry. The first request carrying an
+// Idempotency-Key has its response stored; repeats with the same body get
+// that response back, repeats with a different body are rejected with 422.
+// It must run after JWTAuth so keys are scoped to the caller.
+func (m *Idempotency) WithKey() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return response.WithError(c, failure.BadRequestFromString("idempotency key is too long"))
+		}
+
+		ctx := context.Background()
+		userID, _ := jwt.GetClaims(c)["userID"].(string)
+		redisKey := fmt.Sprintf("idempotency:{%s}:%s:%s", userID, c.Path(), key)
+		fingerprint := m.fingerprint(c)
+
+		locked, err := m.lock(ctx, redisKey, fingerprint)
+		if err != nil {
+			log.Error().Err(err).Msg("[Idempotency] Failed Acquiring Key")
+			return response.WithError(c, failure.InternalError(err))
+		}
+		if !locked {
+			return m.replay(ctx, c, redisKey, fingerprint)
+		}
+
+		err = func() error {
+			// a panicking handler must not keep the key busy forever either
+			defer m.keepLocked(ctx, redisKey)()
+			return c.Next()
+		}()
+		status := c.Response().StatusCode()
+		if err != nil || status >= fiber.StatusInternalServerError {
+			// let the client retry requests that failed on our side
+			m.redis.Client.Del(ctx, redisKey)
+			return err
+		}
+
+		record := idempotencyRecord{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}
+		if err := m.save(ctx, redisKey, record); err != nil {
+			log.Error().Err(err).Msg("[Idempotency] Failed Saving Response")
+		}
+		return nil
+	}
+}
+
+func (m *Idempotency) fingerprint(c *fiber.Ctx) string {
+	sum := sha256.New()
+	sum.Write([]byte(c.Method()))
+	sum.Write([]byte(c.Path()))
+	sum.Write(c.Body())
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+func (m *Idempotency) lock(ctx context.Context, key, fingerprint string) (locked bool, err error) {
+	marshaled, err := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+	if err != nil {
+		return
+	}
+	return m.redis.Client.SetNX(ctx, key, marshaled, idempotencyLockTTL).Result()
+}
+
+// keepLocked renews the lock on key until the returned stop is called, so a
+// request outliving idempotencyLockTTL, e.g. a slow gateway call, cannot be
+// run again by a retry. stop returns once renewal has stopped.
+func (m *Idempotency) keepLocked(ctx context.Context, key string) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(idempotencyLockRenewal)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				err := m.redis.Client.Expire(ctx, key, idempotencyLockTTL).Err()
+				if err != nil {
+					log.Error().Err(err).Msg("[Idempotency] Failed Renewing Key")
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (m *Idempotency) save(ctx context.Context, key string, record idempotencyRecord) (err error) {
+	marshaled, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	expiresIn := m.cfg.Cache.Idempotency.ExpiresIn
+	if expiresIn <= 0 {
+		expiresIn = defaultIdempotencyExpiry
+	}
+	return m.redis.Client.Set(ctx, key, marshaled, expiresIn).Err()
+}
+
+func (m *Idempotency) replay(ctx context.Context, c *fiber.Ctx, key, fingerprint string) error {
+	data, err := m.redis.Client.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return response.WithError(c, failure.Conflict("replay", "idempotency key", "request is being retried, try again"))
+		}
+		log.Error().Err(err).Msg("[Idempotency] Failed Getting Key")
+		return response.WithError(c, failure.InternalError(err))
+	}
+	var record idempotencyRecord
+	err = json.Unmarshal(data, &record)
+	if err != nil {
+		log.Error().Err(err).Msg("[Idempotency] Failed Unmarshal Record")
+		return response.WithError(c, failure.InternalError(err))
+	}
+
+	if record.Fingerprint != fingerprint {
+		return response.WithError(c, failure.UnprocessableEntity("idempotency key was already used with a different request"))
+	}
+	if !record.Completed {
+		return response.WithError(c, failure.Conflict("replay", "idempotency key", "original request is still in progress"))
+	}
+
+	c.Set(IdempotentReplayedHeader, "true")
+	if record.ContentType != "" {
+		c.Set(fiber.HeaderContentType, record.ContentType)
+	}
+	return c.Status(record.Status).Send(record.Body)
+}
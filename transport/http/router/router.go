@@ -3,17 +3,31 @@ package router
 import (
 	"github.com/azka-zaydan/synapsis-test/internal/handlers/auth"
 	"github.com/azka-zaydan/synapsis-test/internal/handlers/cart"
+	"github.com/azka-zaydan/synapsis-test/internal/handlers/category"
+	"github.com/azka-zaydan/synapsis-test/internal/handlers/currency"
+	"github.com/azka-zaydan/synapsis-test/internal/handlers/order"
 	"github.com/azka-zaydan/synapsis-test/internal/handlers/payment"
 	"github.com/azka-zaydan/synapsis-test/internal/handlers/product"
+	"github.com/azka-zaydan/synapsis-test/internal/handlers/promotion"
+	"github.com/azka-zaydan/synapsis-test/internal/handlers/shipping"
+	"github.com/azka-zaydan/synapsis-test/internal/handlers/tax"
+	"github.com/azka-zaydan/synapsis-test/internal/handlers/user"
 	"github.com/gofiber/fiber/v2"
 )
 
 // DomainHandlers is a struct that contains all domain-specific handlers.
 type DomainHandlers struct {
-	AuthHandler    auth.AuthHandler
-	ProductHandler product.ProductHandler
-	CartHandler    cart.CartHandler
-	PaymentHandler payment.PaymentHandler
+	AuthHandler      auth.AuthHandler
+	ProductHandler   product.ProductHandler
+	CategoryHandler  category.CategoryHandler
+	CartHandler      cart.CartHandler
+	PaymentHandler   payment.PaymentHandler
+	OrderHandler     order.OrderHandler
+	CurrencyHandler  currency.CurrencyHandler
+	PromotionHandler promotion.PromotionHandler
+	TaxHandler       tax.TaxHandler
+	ShippingHandler  shipping.ShippingHandler
+	UserHandler      user.UserHandler
 }
 
 // Router is the router struct containing handlers.
@@ -33,7 +47,14 @@ func (r *Router) SetupRoutes(app *fiber.App) {
 	app.Route("/v1", func(router fiber.Router) {
 		r.DomainHandlers.AuthHandler.Router(router)
 		r.DomainHandlers.ProductHandler.Router(router)
+		r.DomainHandlers.CategoryHandler.Router(router)
 		r.DomainHandlers.CartHandler.Router(router)
 		r.DomainHandlers.PaymentHandler.Router(router)
+		r.DomainHandlers.OrderHandler.Router(router)
+		r.DomainHandlers.CurrencyHandler.Router(router)
+		r.DomainHandlers.PromotionHandler.Router(router)
+		r.DomainHandlers.TaxHandler.Router(router)
+		r.DomainHandlers.ShippingHandler.Router(router)
+		r.DomainHandlers.UserHandler.Router(router)
 	})
 }
@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/azka-zaydan/synapsis-test/configs"
+	"github.com/azka-zaydan/synapsis-test/infras"
+	orderSvc "github.com/azka-zaydan/synapsis-test/internal/domain/order/service"
+	reservationSvc "github.com/azka-zaydan/synapsis-test/internal/domain/reservation/service"
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultJobInterval = time.Minute
+
+// Job is a piece of background work run on a fixed interval. Exclusive jobs
+// run on a single replica at a time, elected through a lock in Redis.
+type Job struct {
+	Name      string
+	Interval  time.Duration
+	Exclusive bool
+	Run       func(ctx context.Context) error
+}
+
+// Worker runs the background jobs of this service next to the HTTP server.
+type Worker struct {
+	Config     *configs.Config
+	Redis      *infras.Redis
+	InstanceID string
+	Jobs       []Job
+}
+
+// ProvideWorker is the provider for Worker.
+func ProvideWorker(config *configs.Config, redis *infras.Redis, reservationSvc reservationSvc.ReservationService, orderSvc orderSvc.OrderService) *Worker {
+	instanceID, _ := uuid.NewV4()
+	return &Worker{
+		Config:     config,
+		Redis:      redis,
+		InstanceID: instanceID.String(),
+		Jobs: []Job{
+			{
+				Name:     "reservation-sweeper",
+				Interval: config.Inventory.Reservation.SweepInterval,
+				Run: func(ctx context.Context) error {
+					released, err := reservationSvc.ReleaseExpired(ctx)
+					if released > 0 {
+						log.Info().Int("released", released).Msg("[reservation-sweeper] Released expired reservations")
+					}
+					return err
+				},
+			},
+			{
+				Name:      "order-expiry",
+				Interval:  config.Order.ExpirySweepInterval,
+				Exclusive: true,
+				Run: func(ctx context.Context) error {
+					expired, err := orderSvc.ExpireUnpaidOrders(ctx)
+					if expired > 0 {
+						log.Info().Int("expired", expired).Msg("[order-expiry] Expired unpaid orders")
+					}
+					return err
+				},
+			},
+		},
+	}
+}
+
+// Start launches every job in its own goroutine.
+func (w *Worker) Start() {
+	for _, job := range w.Jobs {
+		go w.run(job)
+	}
+}
+
+func (w *Worker) run(job Job) {
+	interval := job.Interval
+	if interval <= 0 {
+		interval = defaultJobInterval
+	}
+	log.Info().Str("job", job.Name).Str("interval", interval.String()).Msg("Starting background job.")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx := context.Background()
+		if job.Exclusive && !w.isLeader(ctx, job, interval) {
+			continue
+		}
+		if err := job.Run(ctx); err != nil {
+			log.Error().Err(err).Str("job", job.Name).Msg("Background job failed.")
+		}
+	}
+}
+
+// isLeader claims the job for this replica until the next tick. A replica
+// that dies simply lets the lock expire and another one takes over.
+func (w *Worker) isLeader(ctx context.Context, job Job, interval time.Duration) bool {
+	key := fmt.Sprintf("worker:leader:{%s}", job.Name)
+	acquired, err := w.Redis.Client.SetNX(ctx, key, w.InstanceID, interval).Result()
+	if err != nil {
+		log.Error().Err(err).Str("job", job.Name).Msg("Failed acquiring job leadership.")
+		return false
+	}
+	if acquired {
+		return true
+	}
+	owner, err := w.Redis.Client.Get(ctx, key).Result()
+	if err != nil {
+		return false
+	}
+	if owner != w.InstanceID {
+		return false
+	}
+	// still the leader from a previous tick, keep the lock alive
+	w.Redis.Client.Expire(ctx, key, interval)
+	return true
+}
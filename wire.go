@@ -13,22 +13,44 @@ import (
 	authService "github.com/azka-zaydan/synapsis-test/internal/domain/auth/service"
 	cartRepo "github.com/azka-zaydan/synapsis-test/internal/domain/cart/repository"
 	cartSvc "github.com/azka-zaydan/synapsis-test/internal/domain/cart/service"
+	categoryRepo "github.com/azka-zaydan/synapsis-test/internal/domain/category/repository"
+	categorySvc "github.com/azka-zaydan/synapsis-test/internal/domain/category/service"
+	currencyRepo "github.com/azka-zaydan/synapsis-test/internal/domain/currency/repository"
+	currencySvc "github.com/azka-zaydan/synapsis-test/internal/domain/currency/service"
 	orderRepo "github.com/azka-zaydan/synapsis-test/internal/domain/order/repository"
 	orderSvc "github.com/azka-zaydan/synapsis-test/internal/domain/order/service"
+	paymentGateway "github.com/azka-zaydan/synapsis-test/internal/domain/payment/gateway"
 	paymentRepo "github.com/azka-zaydan/synapsis-test/internal/domain/payment/repository"
 	paymentSvc "github.com/azka-zaydan/synapsis-test/internal/domain/payment/service"
 	productRepo "github.com/azka-zaydan/synapsis-test/internal/domain/product/repository"
 	productService "github.com/azka-zaydan/synapsis-test/internal/domain/product/service"
+	promotionRepo "github.com/azka-zaydan/synapsis-test/internal/domain/promotion/repository"
+	promotionSvc "github.com/azka-zaydan/synapsis-test/internal/domain/promotion/service"
+	reservationRepo "github.com/azka-zaydan/synapsis-test/internal/domain/reservation/repository"
+	reservationSvc "github.com/azka-zaydan/synapsis-test/internal/domain/reservation/service"
+	shippingProvider "github.com/azka-zaydan/synapsis-test/internal/domain/shipping/provider"
+	shippingRepo "github.com/azka-zaydan/synapsis-test/internal/domain/shipping/repository"
+	shippingSvc "github.com/azka-zaydan/synapsis-test/internal/domain/shipping/service"
+	taxRepo "github.com/azka-zaydan/synapsis-test/internal/domain/tax/repository"
+	taxSvc "github.com/azka-zaydan/synapsis-test/internal/domain/tax/service"
 	userRepo "github.com/azka-zaydan/synapsis-test/internal/domain/user/repository"
 	userSvc "github.com/azka-zaydan/synapsis-test/internal/domain/user/service"
 	authHandler "github.com/azka-zaydan/synapsis-test/internal/handlers/auth"
 	cartHandler "github.com/azka-zaydan/synapsis-test/internal/handlers/cart"
+	categoryHandler "github.com/azka-zaydan/synapsis-test/internal/handlers/category"
+	currencyHandler "github.com/azka-zaydan/synapsis-test/internal/handlers/currency"
+	orderHandler "github.com/azka-zaydan/synapsis-test/internal/handlers/order"
 	paymentHandler "github.com/azka-zaydan/synapsis-test/internal/handlers/payment"
 	productHandler "github.com/azka-zaydan/synapsis-test/internal/handlers/product"
+	promotionHandler "github.com/azka-zaydan/synapsis-test/internal/handlers/promotion"
+	shippingHandler "github.com/azka-zaydan/synapsis-test/internal/handlers/shipping"
+	taxHandler "github.com/azka-zaydan/synapsis-test/internal/handlers/tax"
+	userHandler "github.com/azka-zaydan/synapsis-test/internal/handlers/user"
 
 	"github.com/azka-zaydan/synapsis-test/transport/http"
 	"github.com/azka-zaydan/synapsis-test/transport/http/middleware"
 	"github.com/azka-zaydan/synapsis-test/transport/http/router"
+	"github.com/azka-zaydan/synapsis-test/transport/worker"
 	"github.com/google/wire"
 )
 
@@ -47,6 +69,10 @@ var authMiddleware = wire.NewSet(
 	middleware.ProvideAuthentication,
 )
 
+var idempotencyMiddleware = wire.NewSet(
+	middleware.ProvideIdempotency,
+)
+
 var domainUser = wire.NewSet(
 	userRepo.ProvideUserRepositoryMySQL,
 	wire.Bind(new(userRepo.UserRepository), new(*userRepo.UserRepositoryMySQL)),
@@ -66,6 +92,13 @@ var domainProduct = wire.NewSet(
 	wire.Bind(new(productService.ProductService), new(*productService.ProductServiceImpl)),
 )
 
+var domainCategory = wire.NewSet(
+	categoryRepo.ProvideCategoryRepositoryMySQL,
+	wire.Bind(new(categoryRepo.CategoryRepository), new(*categoryRepo.CategoryRepositoryMySQL)),
+	categorySvc.ProvideCategoryServiceImpl,
+	wire.Bind(new(categorySvc.CategoryService), new(*categorySvc.CategoryServiceImpl)),
+)
+
 var domainCart = wire.NewSet(
 	cartRepo.ProvideCartRepositoryMySQL,
 	wire.Bind(new(cartRepo.CartRepository), new(*cartRepo.CartRepositoryMySQL)),
@@ -74,6 +107,7 @@ var domainCart = wire.NewSet(
 )
 
 var domainPayment = wire.NewSet(
+	paymentGateway.ProvideRegistry,
 	paymentRepo.ProvidePaymentRepositoryMySQL,
 	wire.Bind(new(paymentRepo.PaymentRepository), new(*paymentRepo.PaymentRepositoryMySQL)),
 	paymentSvc.ProvidePaymentServiceImpl,
@@ -87,9 +121,45 @@ var domainOrder = wire.NewSet(
 	wire.Bind(new(orderSvc.OrderService), new(*orderSvc.OrderServiceImpl)),
 )
 
+var domainReservation = wire.NewSet(
+	reservationRepo.ProvideReservationRepositoryMySQL,
+	wire.Bind(new(reservationRepo.ReservationRepository), new(*reservationRepo.ReservationRepositoryMySQL)),
+	reservationSvc.ProvideReservationServiceImpl,
+	wire.Bind(new(reservationSvc.ReservationService), new(*reservationSvc.ReservationServiceImpl)),
+)
+
+var domainCurrency = wire.NewSet(
+	currencyRepo.ProvideCurrencyRepositoryMySQL,
+	wire.Bind(new(currencyRepo.CurrencyRepository), new(*currencyRepo.CurrencyRepositoryMySQL)),
+	currencySvc.ProvideCurrencyServiceImpl,
+	wire.Bind(new(currencySvc.CurrencyService), new(*currencySvc.CurrencyServiceImpl)),
+)
+
+var domainPromotion = wire.NewSet(
+	promotionRepo.ProvidePromotionRepositoryMySQL,
+	wire.Bind(new(promotionRepo.PromotionRepository), new(*promotionRepo.PromotionRepositoryMySQL)),
+	promotionSvc.ProvidePromotionServiceImpl,
+	wire.Bind(new(promotionSvc.PromotionService), new(*promotionSvc.PromotionServiceImpl)),
+)
+
+var domainTax = wire.NewSet(
+	taxRepo.ProvideTaxRepositoryMySQL,
+	wire.Bind(new(taxRepo.TaxRepository), new(*taxRepo.TaxRepositoryMySQL)),
+	taxSvc.ProvideTaxServiceImpl,
+	wire.Bind(new(taxSvc.TaxService), new(*taxSvc.TaxServiceImpl)),
+)
+
+var domainShipping = wire.NewSet(
+	shippingProvider.ProvideRegistry,
+	shippingRepo.ProvideShippingRepositoryMySQL,
+	wire.Bind(new(shippingRepo.ShippingRepository), new(*shippingRepo.ShippingRepositoryMySQL)),
+	shippingSvc.ProvideShippingServiceImpl,
+	wire.Bind(new(shippingSvc.ShippingService), new(*shippingSvc.ShippingServiceImpl)),
+)
+
 // Wiring for all domains.
 var domains = wire.NewSet(
-	domainAuth, domainUser, domainProduct, domainCart, domainPayment, domainOrder,
+	domainAuth, domainUser, domainProduct, domainCategory, domainCart, domainPayment, domainOrder, domainReservation, domainCurrency, domainPromotion, domainTax, domainShipping,
 )
 
 // Wiring for HTTP routing.
@@ -98,8 +168,15 @@ var routing = wire.NewSet(
 	router.ProvideRouter,
 	authHandler.ProvideAuthHandler,
 	productHandler.ProvideProductHandler,
+	categoryHandler.ProvideCategoryHandler,
 	cartHandler.ProvideCartHandler,
 	paymentHandler.ProvidePaymentHandler,
+	orderHandler.ProvideOrderHandler,
+	currencyHandler.ProvideCurrencyHandler,
+	promotionHandler.ProvidePromotionHandler,
+	taxHandler.ProvideTaxHandler,
+	shippingHandler.ProvideShippingHandler,
+	userHandler.ProvideUserHandler,
 )
 
 // Wiring for everything.
@@ -111,6 +188,7 @@ func InitializeService() *http.HTTP {
 		persistences,
 		// middleware
 		authMiddleware,
+		idempotencyMiddleware,
 		// domains
 		domains,
 		// routing
@@ -119,3 +197,17 @@ func InitializeService() *http.HTTP {
 		http.ProvideHTTP)
 	return &http.HTTP{}
 }
+
+// Wiring for background workers.
+func InitializeWorker() *worker.Worker {
+	wire.Build(
+		// configurations
+		configurations,
+		// persistences
+		persistences,
+		// domains
+		domains,
+		// background jobs
+		worker.ProvideWorker)
+	return &worker.Worker{}
+}